@@ -1,20 +1,35 @@
 package main
 
 import (
+	"context"
 	"log"
-	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"gorm.io/gorm"
 	"github.com/abrahamcruzc/task-manager-go/internal/config"
-	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/handlers"
+	"github.com/abrahamcruzc/task-manager-go/internal/jobs"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
 	"github.com/abrahamcruzc/task-manager-go/internal/routes"
 )
 
 // main es el punto de entrada de la aplicación.
 // Este programa se encarga de cargar la configuración, inicializar la conexión a la base de datos,
 // ejecutar las migraciones necesarias, configurar las rutas de la API y arrancar el servidor HTTP.
+// Al recibir SIGINT o SIGTERM se apaga en orden (ver paso 8).
+// Con el subcomando migrate (ver migrateUsage) solo administra las migraciones de la base de datos.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
 	// 1. Cargar la configuración
 	// Se crea una instancia de la estructura de configuración y se cargan los valores (por ejemplo, desde .env o variables de entorno).
 	cfg := &config.Config{}
@@ -22,8 +37,103 @@ func main() {
 		log.Fatalf("Error loading configurations: %v", err)
 	}
 
-	// 2. Inicializar la base de datos
+	// 2. Inicializar el almacenamiento
+	// Con STORAGE_DRIVER=memory las tareas se guardan en memoria; con postgres o sqlite se conecta a la base de datos
+	// y se ejecutan las migraciones. Las reglas de negocio se aplican en el repositorio de tareas.
+	rules := repository.TaskRules{RequireSubtasksCompleted: cfg.RequireSubtasksCompleted}
+	var repos *repository.Repositories
+	var db *gorm.DB // nil en memoria
+	if cfg.StorageDriver == config.StorageMemory {
+		log.Println("Almacenamiento en memoria: solo está disponible la API de tareas y los datos se pierden al reiniciar")
+		repos = repository.NewMemoryRepositories(rules)
+	} else {
+		db = initDatabase(cfg)
+		repos = repository.NewRepositories(db, rules)
+	}
+
+	// 4. Configurar el router de la API
+	// Se llama a la función SetupRoutes, pasando los repositorios, la configuración de los feeds iCalendar
+	// y los tiempos límite de las consultas, para que se instancien los handlers y se configuren las rutas y middlewares.
+	handler := routes.SetupRoutes(repos, handlers.CalendarOptions{
+		Secret:         cfg.CalendarSecret,
+		AdminToken:     cfg.CalendarAdminToken,
+		TrustedProxies: cfg.TrustedProxies,
+	}, routes.QueryTimeouts{
+		Default: cfg.QueryTimeout,
+		Long:    cfg.LongQueryTimeout,
+	})
+
+	// Los trabajos en segundo plano se detienen al cancelar workersCtx, después de drenar el servidor (ver paso 8)
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	// 5. Iniciar el planificador de recordatorios
+	// Notifica por los canales configurados las tareas que vencen dentro de la ventana de anticipación.
+	if len(cfg.ReminderNotifiers) > 0 {
+		notifier, err := jobs.NewNotifier(cfg.ReminderNotifiers, cfg.ReminderWebhookURL)
+		if err != nil {
+			log.Fatalf("Error configuring reminders: %v", err)
+		}
+		scheduler := jobs.NewReminderScheduler(repos.Tasks, notifier, cfg.ReminderLead, cfg.ReminderInterval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			scheduler.Run(workersCtx)
+		}()
+	}
+
+	// 6. Iniciar la purga de la papelera
+	// Elimina definitivamente las tareas que llevan en la papelera más que el periodo de retención (queda registrado en la auditoría).
+	if cfg.TrashRetentionDays > 0 {
+		purger := jobs.NewTrashPurger(repos.Tasks, cfg.TrashRetention(), cfg.TrashPurgeInterval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			purger.Run(workersCtx)
+		}()
+	}
+
+	// 7. Arrancar el servidor HTTP
+	// Se configura con la dirección, el puerto, los tiempos límite y el tamaño máximo de headers de la configuración.
+	// Atiende solicitudes hasta recibir SIGINT o SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Tras la primera señal se restaura el comportamiento por defecto: una segunda termina el proceso de inmediato
+		<-ctx.Done()
+		stop()
+	}()
+	err := serve(ctx, newServer(cfg, handler), cfg.ShutdownTimeout)
+
+	// 8. Apagado ordenado
+	// Con el servidor ya drenado (sin solicitudes en curso) se detienen los trabajos en segundo plano
+	// y, cuando terminan, se cierran las conexiones a la base de datos que usaban.
+	stopWorkers()
+	workers.Wait()
+	if db != nil {
+		closeDatabase(db)
+	}
+	if err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+	log.Println("Apagado completo")
+}
+
+// initDatabase conecta con la base de datos configurada y aplica las migraciones pendientes (ver migrateOnStart)
+func initDatabase(cfg *config.Config) *gorm.DB {
+	// 3. Inicializar la base de datos
 	// Se utiliza la configuración cargada para establecer una conexión con la base de datos a través de GORM.
+	db := connectDatabase(cfg)
+
+	// 3.1 Migrar el esquema
+	// Se aplican las migraciones SQL versionadas incluidas en el binario (internal/migrations).
+	migrateOnStart(cfg, db)
+	return db
+}
+
+// connectDatabase conecta con la base de datos configurada (reintentando mientras no esté disponible);
+// termina el proceso si no lo consigue
+func connectDatabase(cfg *config.Config) *gorm.DB {
 	var db *gorm.DB
 	var err error
 	maxRetries := 10               // Número máximo de intentos
@@ -41,26 +151,5 @@ func main() {
 	if err != nil {
 		log.Fatalf("No se pudo conectar a la base de datos después de %d intentos: %v", maxRetries, err)
 	}
-
-	// 3. Migrar modelos
-	// Se ejecuta la migración automática de la estructura del modelo Task, creando o actualizando la tabla correspondiente en la base de datos.
-	if err := db.AutoMigrate(&models.Task{}); err != nil {
-		log.Fatalf("Error migrating models: %v", err)
-	}
-
-	// 4. Configurar el router de la API
-	// Se llama a la función SetupRoutes, pasando la conexión a la base de datos,
-	// para que se instancien el repositorio, los handlers y se configuren las rutas y middlewares.
-	handler := routes.SetupRoutes(db)
-
-	// 5. Arrancar el servidor HTTP
-	// Se define la dirección y el puerto en los que se ejecutará el servidor.
-	port := ":8080" // Puerto en el que se escucharán las solicitudes HTTP.
-	addr := "0.0.0.0" // Dirección en la que el servidor estará disponible (escucha en todas las interfaces).
-	log.Printf("Listening at: %s", addr+port)
-	
-	// Se arranca el servidor utilizando ListenAndServe, pasando la dirección completa y el router configurado.
-	if err := http.ListenAndServe(addr+port, handler); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
-	}
-}
\ No newline at end of file
+	return db
+}
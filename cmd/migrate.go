@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/config"
+	"github.com/abrahamcruzc/task-manager-go/internal/migrations"
+	"gorm.io/gorm"
+)
+
+// migrateUsage ayuda del subcomando migrate
+const migrateUsage = `Usage:
+  task-manager migrate up              Apply all pending migrations
+  task-manager migrate down [N]        Revert the last N applied migrations (default 1)
+  task-manager migrate status          List migrations and whether they are applied
+  task-manager migrate create [-dir D] NAME
+                                       Create empty up/down files for every database in D
+                                       (default internal/migrations)
+`
+
+// runMigrate ejecuta el subcomando migrate con sus argumentos (sin "migrate")
+// create solo escribe archivos en el código fuente; el resto se conecta a la base de datos configurada.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return fmt.Errorf("missing migrate command")
+	}
+	command, args := args[0], args[1:]
+
+	if command == "create" {
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := flags.String("dir", "internal/migrations", "directory with one folder of migrations per database")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: migrate create [-dir D] NAME")
+		}
+		paths, err := migrations.Create(*dir, flags.Arg(0))
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return err
+	}
+
+	steps := 1
+	switch command {
+	case "up", "status":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: migrate %s", command)
+		}
+	case "down":
+		if len(args) > 1 {
+			return fmt.Errorf("usage: migrate down [N]")
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %q", args[0])
+			}
+			steps = n
+		}
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+
+	cfg := &config.Config{}
+	if err := cfg.LoadConfig(); err != nil {
+		return err
+	}
+	if cfg.StorageDriver == config.StorageMemory {
+		return fmt.Errorf("STORAGE_DRIVER=%s has no database to migrate", config.StorageMemory)
+	}
+	migrator, err := migrations.NewMigrator(connectDatabase(cfg))
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("Applied", applied)
+		if err == nil && len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		printMigrations("Reverted", reverted)
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("No applied migrations")
+		}
+		return err
+	default:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if s.Missing {
+				applied += " (not in this binary)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	}
+}
+
+// printMigrations muestra una línea por migración aplicada o revertida
+func printMigrations(action string, list []migrations.Migration) {
+	for _, m := range list {
+		fmt.Printf("%s %04d_%s\n", action, m.Version, m.Name)
+	}
+}
+
+// migrateOnStart aplica las migraciones pendientes al arrancar el servidor o, con MIGRATE_ON_START=false,
+// verifica que no haya pendientes; termina el proceso si falla
+// Varias réplicas pueden arrancar a la vez: en PostgreSQL solo una aplica las migraciones (bloqueo consultivo).
+func migrateOnStart(cfg *config.Config, db *gorm.DB) {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Error loading migrations: %v", err)
+	}
+	ctx := context.Background()
+	if cfg.MigrateOnStart {
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("Migración aplicada: %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Error applying migrations: %v", err)
+		}
+		return
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatalf("Error checking migrations: %v", err)
+	}
+	pending := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		log.Fatalf("There are %d pending migrations; run \"migrate up\" or set MIGRATE_ON_START=true", pending)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/config"
+	"gorm.io/gorm"
+)
+
+// newServer crea el servidor HTTP con la dirección, los tiempos límite y el tamaño máximo de headers configurados
+func newServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.ServerAddr(),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// serve atiende solicitudes hasta que se cancele ctx (SIGINT o SIGTERM) y luego drena el servidor:
+// deja de aceptar conexiones y espera a las solicitudes en curso durante shutdownTimeout (0 = sin límite);
+// las que no terminan a tiempo se interrumpen cerrando sus conexiones
+// Retorna: error si el servidor no pudo arrancar o si hubo que interrumpir solicitudes
+func serve(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Listening at: %s", srv.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server failed to start: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("Apagando el servidor: esperando a que terminen las solicitudes en curso")
+	shutdownCtx := context.Background()
+	if shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, shutdownTimeout)
+		defer cancel()
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("requests still running after %s were interrupted: %w", shutdownTimeout, err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Println("Servidor HTTP detenido")
+	return nil
+}
+
+// closeDatabase cierra el pool de conexiones de la base de datos
+func closeDatabase(db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		log.Printf("Error closing database: %v", err)
+		return
+	}
+	log.Println("Conexiones a la base de datos cerradas")
+}
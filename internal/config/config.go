@@ -3,22 +3,73 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// Backends de almacenamiento admitidos en STORAGE_DRIVER
+const (
+	StoragePostgres = "postgres" // PostgreSQL (por defecto), configurado con las variables DB_*
+	StorageSQLite   = "sqlite"   // Archivo SQLite embebido en SQLITE_PATH, sin servidor de base de datos
+	StorageMemory   = "memory"   // Tareas en memoria, sin base de datos (solo la API de tareas; los datos se pierden al reiniciar)
+)
+
 // Config contiene la configuración de la aplicación mapeada desde variables de entorno
 // Campos corresponden a las variables de entorno con la nomenclatura DB_*
 type Config struct {
+	StorageDriver  string `mapstructure:"STORAGE_DRIVER"`   // Backend de almacenamiento: postgres, sqlite o memory
+	SQLitePath     string `mapstructure:"SQLITE_PATH"`      // Archivo de la base de datos SQLite
+	MigrateOnStart bool   `mapstructure:"MIGRATE_ON_START"` // Aplicar las migraciones pendientes al arrancar (false = el arranque falla si hay pendientes)
+
 	DBHost     string `mapstructure:"DB_HOST"`     // Host de la base de datos
 	DBPort     string `mapstructure:"DB_PORT"`     // Puerto de la base de datos
 	DBUser     string `mapstructure:"DB_USER"`     // Usuario de la base de datos
 	DBPassword string `mapstructure:"DB_PASSWORD"` // Contraseña del usuario
 	DBName     string `mapstructure:"DB_NAME"`     // Nombre de la base de datos
 	SSLMode    string `mapstructure:"SSL_MODE"`    // Modo SSL para la conexión
+
+	// Servidor HTTP
+	BindAddress       string        `mapstructure:"BIND_ADDRESS"`             // Dirección en la que escucha el servidor (0.0.0.0 = todas las interfaces)
+	Port              int           `mapstructure:"PORT"`                     // Puerto del servidor
+	ReadTimeout       time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`        // Tiempo máximo para leer la solicitud completa, incluido el cuerpo (0 = sin límite)
+	ReadHeaderTimeout time.Duration `mapstructure:"HTTP_READ_HEADER_TIMEOUT"` // Tiempo máximo para leer los headers (0 = sin límite)
+	WriteTimeout      time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`       // Tiempo máximo para escribir la respuesta (0 = sin límite)
+	IdleTimeout       time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`        // Tiempo que una conexión keep-alive permanece inactiva
+	MaxHeaderBytes    int           `mapstructure:"HTTP_MAX_HEADER_BYTES"`    // Tamaño máximo de los headers de una solicitud
+	ShutdownTimeout   time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`         // Espera máxima a las solicitudes en curso al apagar el servidor
+
+	// Recordatorios de tareas próximas a vencer
+	ReminderNotifiers  []string      `mapstructure:"REMINDER_NOTIFIERS"`    // Canales: "log", "webhook" (vacío = desactivado)
+	ReminderLead       time.Duration `mapstructure:"REMINDER_LEAD_MINUTES"` // Minutos de anticipación antes de la fecha límite
+	ReminderInterval   time.Duration `mapstructure:"REMINDER_INTERVAL"`     // Frecuencia de revisión (ej. "1m")
+	ReminderWebhookURL string        `mapstructure:"REMINDER_WEBHOOK_URL"`  // URL que recibe los recordatorios vía POST
+
+	// Papelera de tareas eliminadas
+	TrashRetentionDays int           `mapstructure:"TRASH_RETENTION_DAYS"` // Días que una tarea eliminada permanece recuperable (0 = sin purga automática)
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"` // Frecuencia de la purga (ej. "1h")
+
+	// Límites de tiempo de las consultas de cada solicitud
+	QueryTimeout     time.Duration `mapstructure:"QUERY_TIMEOUT"`      // Solicitudes comunes (ej. "10s"; 0 = sin límite)
+	LongQueryTimeout time.Duration `mapstructure:"LONG_QUERY_TIMEOUT"` // Lotes, importaciones, exportaciones, feeds y auditoría (0 = sin límite)
+
+	// Feeds iCalendar
+	CalendarSecret     string `mapstructure:"CALENDAR_SECRET"`      // Secreto con el que se firman los tokens de las URLs de los feeds (vacío = feeds desactivados)
+	CalendarAdminToken string `mapstructure:"CALENDAR_ADMIN_TOKEN"` // Token Bearer para consultar y renovar las URLs de los feeds (vacío = no se exponen)
+
+	// Proxies inversos de confianza: solo se respetan sus headers X-Forwarded-* (ej. al construir las URLs de los feeds)
+	TrustedProxies []netip.Prefix `mapstructure:"TRUSTED_PROXIES"` // IPs o rangos CIDR (vacío = ninguno)
+
+	// Reglas de negocio de las tareas
+	RequireSubtasksCompleted bool `mapstructure:"REQUIRE_SUBTASKS_COMPLETED"` // Impide completar (estado done) una tarea con subtareas abiertas
 }
 
 // LoadConfig carga la configuración desde variables de entorno y .env
@@ -33,28 +84,249 @@ func (c *Config) LoadConfig() error {
 	}
 
 	// 2. Asigna valores directamente desde las variables de entorno
+	c.StorageDriver = strings.ToLower(os.Getenv("STORAGE_DRIVER"))
+	if c.StorageDriver == "" {
+		c.StorageDriver = StoragePostgres
+	}
+	c.SQLitePath = os.Getenv("SQLITE_PATH")
+	if c.SQLitePath == "" {
+		c.SQLitePath = "task-manager.db"
+	}
+	migrateOnStart, err := getBoolEnv("MIGRATE_ON_START", true)
+	if err != nil {
+		return err
+	}
+	c.MigrateOnStart = migrateOnStart
 	c.DBHost = os.Getenv("DB_HOST")
 	c.DBPort = os.Getenv("DB_PORT")
 	c.DBUser = os.Getenv("DB_USER")
 	c.DBPassword = os.Getenv("DB_PASSWORD")
 	c.DBName = os.Getenv("DB_NAME")
 	c.SSLMode = os.Getenv("SSL_MODE")
-	
+
+	if err := c.loadServerConfig(); err != nil {
+		return err
+	}
+
+	c.ReminderNotifiers = getListEnv("REMINDER_NOTIFIERS", []string{"log"})
+	c.ReminderWebhookURL = os.Getenv("REMINDER_WEBHOOK_URL")
+	leadMinutes, err := getIntEnv("REMINDER_LEAD_MINUTES", 15)
+	if err != nil {
+		return err
+	}
+	if leadMinutes < 0 {
+		return fmt.Errorf("valor inválido para REMINDER_LEAD_MINUTES: %d es negativo", leadMinutes)
+	}
+	c.ReminderLead = time.Duration(leadMinutes) * time.Minute
+	if c.ReminderInterval, err = getDurationEnv("REMINDER_INTERVAL", time.Minute); err != nil {
+		return err
+	}
+	if c.ReminderInterval <= 0 {
+		return fmt.Errorf("valor inválido para REMINDER_INTERVAL: %s (debe ser mayor que 0)", c.ReminderInterval)
+	}
+
+	if c.TrashRetentionDays, err = getIntEnv("TRASH_RETENTION_DAYS", 30); err != nil {
+		return err
+	}
+	if c.TrashRetentionDays < 0 {
+		return fmt.Errorf("valor inválido para TRASH_RETENTION_DAYS: %d es negativo", c.TrashRetentionDays)
+	}
+	if c.TrashPurgeInterval, err = getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour); err != nil {
+		return err
+	}
+	if c.TrashPurgeInterval <= 0 {
+		return fmt.Errorf("valor inválido para TRASH_PURGE_INTERVAL: %s (debe ser mayor que 0)", c.TrashPurgeInterval)
+	}
+
+	if c.QueryTimeout, err = getDurationEnv("QUERY_TIMEOUT", 10*time.Second); err != nil {
+		return err
+	}
+	if c.LongQueryTimeout, err = getDurationEnv("LONG_QUERY_TIMEOUT", 2*time.Minute); err != nil {
+		return err
+	}
+	if c.QueryTimeout < 0 || c.LongQueryTimeout < 0 {
+		return fmt.Errorf("valor inválido para QUERY_TIMEOUT o LONG_QUERY_TIMEOUT: la duración no puede ser negativa")
+	}
+
+	c.CalendarSecret = os.Getenv("CALENDAR_SECRET")
+	c.CalendarAdminToken = os.Getenv("CALENDAR_ADMIN_TOKEN")
+	if c.TrustedProxies, err = getPrefixListEnv("TRUSTED_PROXIES"); err != nil {
+		return err
+	}
+
+	if c.RequireSubtasksCompleted, err = getBoolEnv("REQUIRE_SUBTASKS_COMPLETED", false); err != nil {
+		return err
+	}
 
 	// 3. Valida campos obligatorios
-	if c.DBUser == "" || c.DBPassword == "" || c.DBName == "" {
-		return fmt.Errorf("configuración incompleta: DB_USER, DB_PASSWORD y DB_NAME son requeridos")
+	switch c.StorageDriver {
+	case StoragePostgres:
+		if c.DBUser == "" || c.DBPassword == "" || c.DBName == "" {
+			return fmt.Errorf("configuración incompleta: DB_USER, DB_PASSWORD y DB_NAME son requeridos")
+		}
+	case StorageSQLite, StorageMemory:
+	default:
+		return fmt.Errorf("valor inválido para STORAGE_DRIVER: %q (debe ser %s, %s o %s)",
+			c.StorageDriver, StoragePostgres, StorageSQLite, StorageMemory)
+	}
+	for _, n := range c.ReminderNotifiers {
+		if n == "webhook" && c.ReminderWebhookURL == "" {
+			return fmt.Errorf("configuración incompleta: REMINDER_WEBHOOK_URL es requerido para el notificador webhook")
+		}
+	}
+
+	switch c.StorageDriver {
+	case StoragePostgres:
+		log.Printf("Configuración cargada: Host=%s, Port=%s, DB=%s", c.DBHost, c.DBPort, c.DBName)
+	case StorageSQLite:
+		log.Printf("Configuración cargada: SQLite=%s", c.SQLitePath)
+	default:
+		log.Printf("Configuración cargada: almacenamiento en memoria")
+	}
+	return nil
+}
+
+// loadServerConfig asigna y valida la configuración del servidor HTTP
+func (c *Config) loadServerConfig() error {
+	c.BindAddress = getEnv("BIND_ADDRESS", "0.0.0.0")
+	var err error
+	if c.Port, err = getIntEnv("PORT", 8080); err != nil {
+		return err
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("valor inválido para PORT: %d (debe estar entre 1 y 65535)", c.Port)
+	}
+	if c.MaxHeaderBytes, err = getIntEnv("HTTP_MAX_HEADER_BYTES", 1<<20); err != nil {
+		return err
+	}
+	if c.MaxHeaderBytes <= 0 {
+		return fmt.Errorf("valor inválido para HTTP_MAX_HEADER_BYTES: %d (debe ser positivo)", c.MaxHeaderBytes)
 	}
 
-	log.Printf("Configuración cargada: Host=%s, Port=%s, DB=%s", c.DBHost, c.DBPort, c.DBName)
+	durations := []struct {
+		key string
+		dst *time.Duration
+		def time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", &c.ReadTimeout, 30 * time.Second},
+		{"HTTP_READ_HEADER_TIMEOUT", &c.ReadHeaderTimeout, 10 * time.Second},
+		// Mayor que LONG_QUERY_TIMEOUT para que las exportaciones y los feeds puedan terminar de escribirse
+		{"HTTP_WRITE_TIMEOUT", &c.WriteTimeout, 3 * time.Minute},
+		{"HTTP_IDLE_TIMEOUT", &c.IdleTimeout, 2 * time.Minute},
+		{"SHUTDOWN_TIMEOUT", &c.ShutdownTimeout, 30 * time.Second},
+	}
+	for _, d := range durations {
+		if *d.dst, err = getDurationEnv(d.key, d.def); err != nil {
+			return err
+		}
+		if *d.dst < 0 {
+			return fmt.Errorf("valor inválido para %s: la duración no puede ser negativa", d.key)
+		}
+	}
 	return nil
 }
 
-// InitDb establece la conexión con PostgreSQL usando GORM
+// TrashRetention tiempo que una tarea eliminada permanece en la papelera antes de la purga automática
+func (c *Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
+}
+
+// ServerAddr dirección host:puerto en la que escucha el servidor HTTP
+func (c *Config) ServerAddr() string {
+	return net.JoinHostPort(c.BindAddress, strconv.Itoa(c.Port))
+}
+
+// getEnv lee una variable de entorno, usando def si no está definida o está vacía
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// getIntEnv lee una variable de entorno entera, usando def si no está definida
+func getIntEnv(key string, def int) (int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido para %s: %q no es un entero", key, raw)
+	}
+	return n, nil
+}
+
+// getBoolEnv lee una variable de entorno booleana (true/false/1/0), usando def si no está definida
+func getBoolEnv(key string, def bool) (bool, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("valor inválido para %s: %q no es un booleano", key, raw)
+	}
+	return b, nil
+}
+
+// getDurationEnv lee una variable de entorno con formato de duración de Go (ej. "30s", "5m")
+func getDurationEnv(key string, def time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido para %s: %q no es una duración", key, raw)
+	}
+	return d, nil
+}
+
+// getListEnv lee una lista separada por comas; una variable definida pero vacía produce una lista vacía
+func getListEnv(key string, def []string) []string {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getPrefixListEnv lee una lista separada por comas de IPs o rangos CIDR; una IP equivale a un rango de un solo host
+func getPrefixListEnv(key string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range getListEnv(key, nil) {
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(item)
+			if addrErr != nil {
+				return nil, fmt.Errorf("valor inválido para %s: %q no es una IP ni un rango CIDR", key, item)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// InitDb establece la conexión con la base de datos del backend configurado (PostgreSQL o SQLite) usando GORM
 // Retorna:
 // - Instancia de GORM DB para operaciones de base de datos
-// - error detallado si falla la conexión
+// - error detallado si falla la conexión, o si el backend (memory) no usa base de datos
 func (c *Config) InitDb() (*gorm.DB, error) {
+	switch c.StorageDriver {
+	case StorageSQLite:
+		return c.initSQLite()
+	case StorageMemory:
+		return nil, fmt.Errorf("el almacenamiento %q no usa base de datos", StorageMemory)
+	}
+
 	// Construye el DSN (Data Source Name)
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -67,7 +339,8 @@ func (c *Config) InitDb() (*gorm.DB, error) {
 	)
 
 	// Establece la conexión con PostgreSQL
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError convierte errores del driver (ej. violación de índice único) en errores de GORM
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, fmt.Errorf("fallo en conexión a PostgreSQL: %v\nDSN usado: %s", err, dsn)
 	}
@@ -81,3 +354,20 @@ func (c *Config) InitDb() (*gorm.DB, error) {
 	log.Println("Conexión a PostgreSQL establecida exitosamente")
 	return db, nil
 }
+
+// initSQLite abre (o crea) el archivo SQLite configurado con el driver en Go puro, sin cgo
+// Se activan las claves foráneas (desactivadas por defecto en SQLite), el modo WAL para que las lecturas
+// no esperen a las escrituras, y una espera ante bloqueos en lugar de fallar con "database is locked".
+// Las transacciones toman el bloqueo de escritura al comenzar (BEGIN IMMEDIATE): SQLite no tiene bloqueos
+// de fila (SELECT ... FOR UPDATE), así que las comprobaciones que el repositorio hace dentro de una
+// transacción antes de escribir no pueden quedar desactualizadas por otra escritura.
+func (c *Config) initSQLite() (*gorm.DB, error) {
+	dsn := c.SQLitePath + "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("fallo al abrir la base de datos SQLite %s: %v", c.SQLitePath, err)
+	}
+
+	log.Printf("Base de datos SQLite abierta: %s", c.SQLitePath)
+	return db, nil
+}
@@ -0,0 +1,237 @@
+package formats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// csvColumns columnas del CSV exportado, en orden
+var csvColumns = []string{
+	"id", "project_id", "parent_id", "name", "description", "status", "status_category", "priority",
+	"start_at", "due_at", "tags", "version", "created_at", "updated_at",
+}
+
+// csvTagSeparator separa los nombres de las etiquetas dentro de la columna tags
+const csvTagSeparator = ";"
+
+// csvFormulaPrefixes caracteres iniciales con los que una hoja de cálculo interpreta una celda como fórmula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+func init() {
+	Register(&Format{
+		Name:        "csv",
+		ContentType: "text/csv",
+		Extension:   "csv",
+		NewEncoder:  func(w io.Writer) Encoder { return &csvEncoder{w: csv.NewWriter(w)} },
+		NewDecoder:  func(r io.Reader) Decoder { return newCSVDecoder(r) },
+	})
+}
+
+// csvEncoder escribe una cabecera con csvColumns y una fila por tarea
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// Encode implementa Encoder
+func (e *csvEncoder) Encode(task *models.Task) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	tags := make([]string, len(task.Tags))
+	for i, tag := range task.Tags {
+		tags[i] = tag.Name
+	}
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(task.ID), 10),
+		formatID(task.ProjectID),
+		formatID(task.ParentID),
+		escapeCSVText(task.Name),
+		escapeCSVText(task.Description),
+		string(task.Status),
+		string(task.StatusCategory),
+		string(task.Priority),
+		formatTime(task.StartAt),
+		formatTime(task.DueAt),
+		strings.Join(tags, csvTagSeparator),
+		strconv.FormatUint(uint64(task.Version), 10),
+		task.CreatedAt.UTC().Format(time.RFC3339),
+		task.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// Close implementa Encoder; un archivo sin tareas contiene solo la cabecera
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// writeHeader escribe la cabecera una sola vez
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.w.Write(csvColumns)
+}
+
+// escapeCSVText neutraliza un texto que una hoja de cálculo ejecutaría como fórmula anteponiéndole ',
+// que se quita al importar (ver unescapeCSVText); también se antepone a los textos que ya empiezan con '
+func escapeCSVText(s string) string {
+	if s != "" && (strings.ContainsRune(csvFormulaPrefixes, rune(s[0])) || s[0] == '\'') {
+		return "'" + s
+	}
+	return s
+}
+
+// unescapeCSVText revierte escapeCSVText
+func unescapeCSVText(s string) string {
+	return strings.TrimPrefix(s, "'")
+}
+
+// formatID convierte un ID opcional en texto (vacío si es nil)
+func formatID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// formatTime convierte una fecha opcional a RFC 3339 en UTC (vacío si es nil)
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// csvDecoder lee un CSV con cabecera; las columnas se identifican por nombre y en cualquier orden,
+// y las que no son campos importables se ignoran
+type csvDecoder struct {
+	r       *csv.Reader
+	columns []string // Nombre de cada columna según la cabecera
+}
+
+// newCSVDecoder crea el decoder CSV
+func newCSVDecoder(r io.Reader) *csvDecoder {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // El número de columnas se valida por fila
+	reader.TrimLeadingSpace = true
+	return &csvDecoder{r: reader}
+}
+
+// Next implementa Decoder
+func (d *csvDecoder) Next() (*Row, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := d.r.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	line, _ := d.r.FieldPos(0)
+	row := &Row{Line: line}
+	if len(record) != len(d.columns) {
+		row.Err = models.ValidationErrors{{
+			Field:   "row",
+			Message: fmt.Sprintf("expected %d columns, got %d", len(d.columns), len(record)),
+		}}
+		return row, nil
+	}
+
+	fields := make(map[string]interface{}, len(record))
+	var errs models.ValidationErrors
+	for i, column := range d.columns {
+		if !isImportField(column) {
+			continue
+		}
+		value, err := parseCSVValue(column, strings.TrimSpace(record[i]))
+		if err != nil {
+			errs.Add(column, err.Error())
+			continue
+		}
+		if value != nil {
+			fields[column] = value
+		}
+	}
+	if err := errs.Err(); err != nil {
+		row.Err = err
+		return row, nil
+	}
+	row.Fields, _ = json.Marshal(fields)
+	return row, nil
+}
+
+// readHeader lee la cabecera y verifica que incluya la columna name
+func (d *csvDecoder) readHeader() error {
+	header, err := d.r.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns := make([]string, len(header))
+	hasName := false
+	for i, column := range header {
+		// Las hojas de cálculo suelen anteponer un BOM UTF-8 a la primera columna
+		columns[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		hasName = hasName || columns[i] == "name"
+	}
+	if !hasName {
+		return fmt.Errorf("invalid CSV header: a name column is required")
+	}
+	d.columns = columns
+	return nil
+}
+
+// parseCSVValue convierte el texto de una celda al valor JSON del campo
+// Las celdas vacías de IDs y fechas se importan como null; las de status y priority se omiten
+// (la tarea conserva su valor o recibe el valor por defecto).
+func parseCSVValue(column, raw string) (interface{}, error) {
+	switch column {
+	case "project_id", "parent_id":
+		if raw == "" {
+			return json.RawMessage("null"), nil
+		}
+		id, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("%s must be a positive integer", column)
+		}
+		return id, nil
+	case "start_at", "due_at":
+		if raw == "" {
+			return json.RawMessage("null"), nil
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", column)
+	case "status", "priority":
+		if raw == "" {
+			return nil, nil
+		}
+		return raw, nil
+	case "name", "description":
+		return unescapeCSVText(raw), nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,110 @@
+// Package formats implementa los formatos de archivo para exportar e importar tareas
+package formats
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// Encoder escribe tareas en un formato de archivo
+type Encoder interface {
+	Encode(task *models.Task) error // Escribe una tarea
+	Close() error                   // Completa el documento (ej. cierra el arreglo JSON) y vacía el buffer
+}
+
+// Decoder lee las filas de un archivo de importación
+type Decoder interface {
+	// Next retorna la siguiente fila, io.EOF al terminar, u otro error si el archivo está dañado
+	// y no puede seguir leyéndose (los errores de una sola fila se informan en Row.Err)
+	Next() (*Row, error)
+}
+
+// Row fila de un archivo de importación
+type Row struct {
+	Line   int             // Número de fila en el archivo (en CSV la cabecera es la fila 1; en JSON, la posición del elemento)
+	Fields json.RawMessage // Objeto JSON con los campos importables presentes en la fila (ver ImportFields)
+	Tags   []string        // Etiquetas de la tarea, en los formatos que las importan (nil = no se modifican)
+	Err    error           // Error de la fila (ej. ValidationErrors por un valor con formato inválido)
+	// Categoría del estado en los formatos que solo la informan (iCalendar); la tarea recibe el primer estado
+	// de esa categoría en el flujo de su proyecto ("" = el estado es el de Fields)
+	Category models.StatusCategory
+}
+
+// ImportFields campos de una tarea que se leen al importar; el resto (id, version, fechas de auditoría...)
+// se exportan pero se ignoran al importar. Las etiquetas solo se importan en los formatos que las
+// informan en Row.Tags (Markdown y todo.txt).
+var ImportFields = []string{"project_id", "parent_id", "name", "description", "status", "priority", "start_at", "due_at"}
+
+// isImportField indica si la clave es un campo importable
+func isImportField(key string) bool {
+	for _, field := range ImportFields {
+		if field == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Format formato de archivo registrado
+type Format struct {
+	Name        string // Nombre usado en el parámetro format (ej. csv)
+	ContentType string // Tipo MIME de la respuesta de exportación
+	Extension   string // Extensión de archivo, sin punto
+	NewEncoder  func(w io.Writer) Encoder
+	NewDecoder  func(r io.Reader) Decoder
+}
+
+// registry formatos disponibles por nombre
+var registry = map[string]*Format{}
+
+// Register agrega un formato al registro; un formato con el mismo nombre se reemplaza
+func Register(f *Format) {
+	registry[f.Name] = f
+}
+
+// Lookup busca un formato por su nombre
+func Lookup(name string) (*Format, bool) {
+	f, ok := registry[strings.ToLower(name)]
+	return f, ok
+}
+
+// ByFilename busca un formato por la extensión del nombre de archivo
+func ByFilename(filename string) (*Format, bool) {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	for _, f := range registry {
+		if ext != "" && f.Extension == ext {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// ByContentType busca un formato por su tipo MIME (se ignoran parámetros como charset)
+func ByContentType(contentType string) (*Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	for _, f := range registry {
+		if f.ContentType == mediaType {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Names retorna los nombres de los formatos registrados, ordenados alfabéticamente
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
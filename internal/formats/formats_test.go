@@ -0,0 +1,159 @@
+package formats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/formats"
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// roundTrip exporta las tareas en el formato indicado y retorna las filas que se leen al importarlas
+func roundTrip(t *testing.T, name string, tasks []*models.Task) []*formats.Row {
+	t.Helper()
+	format, ok := formats.Lookup(name)
+	if !ok {
+		t.Fatalf("format %s is not registered", name)
+	}
+	var buf bytes.Buffer
+	enc := format.NewEncoder(&buf)
+	for _, task := range tasks {
+		if err := enc.Encode(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return decodeRows(t, format, buf.String())
+}
+
+// decodeRows lee todas las filas del archivo
+func decodeRows(t *testing.T, format *formats.Format, file string) []*formats.Row {
+	t.Helper()
+	dec := format.NewDecoder(strings.NewReader(file))
+	var rows []*formats.Row
+	for {
+		row, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("decode %q: %v", file, err)
+		}
+		if row.Err != nil {
+			t.Fatalf("row %d of %q: %v", row.Line, file, row.Err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// rowFields campos de la fila que comprueban las pruebas
+type rowFields struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func fieldsOf(t *testing.T, row *formats.Row) rowFields {
+	t.Helper()
+	var fields rowFields
+	if err := json.Unmarshal(row.Fields, &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestTodoTxtRoundTripsNames(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	names := []string{
+		"Email +alice about @home",
+		"Review due:tomorrow and status:draft notes",
+		"x marks the spot",
+		"(A) first",
+		"2026-01-01 retrospective",
+		`C:\temp \cleanup`,
+	}
+	var tasks []*models.Task
+	for _, name := range names {
+		task := &models.Task{Name: name, Description: "pri:A +not-a-tag", Status: models.ToDo,
+			StatusCategory: models.CategoryTodo, Priority: models.PriorityMedium, Tags: []models.Tag{{Name: "work"}}}
+		task.CreatedAt = created
+		tasks = append(tasks, task)
+	}
+
+	rows := roundTrip(t, "todotxt", tasks)
+	if len(rows) != len(names) {
+		t.Fatalf("rows = %d, want %d", len(rows), len(names))
+	}
+	for i, row := range rows {
+		want := rowFields{Name: names[i], Description: "pri:A +not-a-tag"}
+		if got := fieldsOf(t, row); got != want {
+			t.Errorf("row %d = %+v, want %+v", i, got, want)
+		}
+		if !reflect.DeepEqual(row.Tags, []string{"work"}) {
+			t.Errorf("row %d tags = %v, want [work]", i, row.Tags)
+		}
+	}
+}
+
+func TestMarkdownNestedItems(t *testing.T) {
+	format, _ := formats.Lookup("markdown")
+	rows := decodeRows(t, format, "- [ ] Parent\n  Details\n  - [x] Child\n    Child details\n- [ ] Next\n")
+	var got []rowFields
+	for _, row := range rows {
+		got = append(got, fieldsOf(t, row))
+	}
+	want := []rowFields{{"Parent", "Details"}, {"Child", "Child details"}, {"Next", ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %+v, want nested items as separate tasks %+v", got, want)
+	}
+
+	rows = roundTrip(t, "markdown", []*models.Task{{Name: "Checklist", Description: "- [ ] not a task\n\\literal",
+		Status: models.ToDo, StatusCategory: models.CategoryTodo, Priority: models.PriorityLow}})
+	if len(rows) != 1 || fieldsOf(t, rows[0]).Description != "- [ ] not a task\n\\literal" {
+		t.Errorf("description with item markers did not round-trip: %d rows", len(rows))
+	}
+}
+
+func TestCSVNeutralizesFormulas(t *testing.T) {
+	names := []string{"=SUM(A1:A2)", "+1 follow-up", "-2 days", "@mention", "'quoted'", "Plain = name"}
+	var tasks []*models.Task
+	for _, name := range names {
+		tasks = append(tasks, &models.Task{Name: name, Description: "=HYPERLINK(\"http://example.com\")",
+			Status: models.ToDo, StatusCategory: models.CategoryTodo, Priority: models.PriorityLow})
+	}
+
+	format, _ := formats.Lookup("csv")
+	var buf bytes.Buffer
+	enc := format.NewEncoder(&buf)
+	for _, task := range tasks {
+		if err := enc.Encode(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range []string{",'=SUM(A1:A2),", ",'+1 follow-up,", ",'-2 days,", ",'@mention,", ",''quoted',", ",Plain = name,"} {
+		if !strings.Contains(buf.String(), cell) {
+			t.Errorf("export does not contain %q:\n%s", cell, buf.String())
+		}
+	}
+
+	rows := decodeRows(t, format, buf.String())
+	if len(rows) != len(names) {
+		t.Fatalf("rows = %d, want %d", len(rows), len(names))
+	}
+	for i, row := range rows {
+		want := rowFields{Name: names[i], Description: "=HYPERLINK(\"http://example.com\")"}
+		if got := fieldsOf(t, row); got != want {
+			t.Errorf("row %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
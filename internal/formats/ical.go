@@ -0,0 +1,412 @@
+package formats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// Constantes del formato iCalendar (RFC 5545)
+const (
+	icalProdID       = "-//abrahamcruzc//task-manager-go//EN"
+	icalUIDDomain    = "task-manager-go" // Dominio de los UID de las tareas: task-<id>@task-manager-go
+	icalLineLimit    = 75                // Octetos por línea antes de plegarla
+	icalDateTime     = "20060102T150405Z"
+	icalLocalTime    = "20060102T150405"
+	icalDate         = "20060102"
+	maxICalLineSize  = 1 << 20
+	icalNewline      = "\r\n"
+	icalFoldedPrefix = " "
+)
+
+func init() {
+	Register(&Format{
+		Name:        "ics",
+		ContentType: "text/calendar",
+		Extension:   "ics",
+		NewEncoder:  func(w io.Writer) Encoder { return &icalEncoder{w: bufio.NewWriter(w)} },
+		NewDecoder:  newICalDecoder,
+	})
+}
+
+// TaskUID retorna el UID iCalendar de la tarea
+func TaskUID(id uint) string {
+	return fmt.Sprintf("task-%d@%s", id, icalUIDDomain)
+}
+
+// icalStatus estado VTODO según la categoría del estado de la tarea en su flujo de trabajo
+func icalStatus(category models.StatusCategory) string {
+	switch category {
+	case models.CategoryActive:
+		return "IN-PROCESS"
+	case models.CategoryDone:
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// icalPriority prioridad iCalendar (1 = máxima, 9 = mínima)
+func icalPriority(p models.Priority) int {
+	switch p {
+	case models.PriorityUrgent:
+		return 1
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityLow:
+		return 9
+	}
+	return 5
+}
+
+// taskPriority convierte una prioridad iCalendar en la de la tarea ("" si es 0 o inválida)
+func taskPriority(raw string) models.Priority {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	switch {
+	case err != nil || n <= 0 || n > 9:
+		return ""
+	case n <= 2:
+		return models.PriorityUrgent
+	case n <= 4:
+		return models.PriorityHigh
+	case n == 5:
+		return models.PriorityMedium
+	}
+	return models.PriorityLow
+}
+
+// icalEncoder escribe un VCALENDAR con un VTODO por tarea
+type icalEncoder struct {
+	w       *bufio.Writer
+	started bool
+	err     error
+}
+
+// Encode implementa Encoder
+func (e *icalEncoder) Encode(task *models.Task) error {
+	e.begin()
+	e.line("BEGIN:VTODO")
+	e.line("UID:" + TaskUID(task.ID))
+	e.line("DTSTAMP:" + task.UpdatedAt.UTC().Format(icalDateTime))
+	e.line("CREATED:" + task.CreatedAt.UTC().Format(icalDateTime))
+	e.line("LAST-MODIFIED:" + task.UpdatedAt.UTC().Format(icalDateTime))
+	if task.Version > 0 {
+		e.line("SEQUENCE:" + strconv.FormatUint(uint64(task.Version-1), 10))
+	}
+	e.line("SUMMARY:" + icalEscape(task.Name))
+	if task.Description != "" {
+		e.line("DESCRIPTION:" + icalEscape(task.Description))
+	}
+	e.line("STATUS:" + icalStatus(task.StatusCategory))
+	e.line("PRIORITY:" + strconv.Itoa(icalPriority(task.Priority)))
+	if task.StartAt != nil {
+		e.line("DTSTART:" + task.StartAt.UTC().Format(icalDateTime))
+	}
+	if task.DueAt != nil {
+		e.line("DUE:" + task.DueAt.UTC().Format(icalDateTime))
+	}
+	if len(task.Tags) > 0 {
+		names := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			names[i] = icalEscape(tag.Name)
+		}
+		e.line("CATEGORIES:" + strings.Join(names, ","))
+	}
+	if task.ParentID != nil {
+		e.line("RELATED-TO;RELTYPE=PARENT:" + TaskUID(*task.ParentID))
+	}
+	e.line("END:VTODO")
+	return e.err
+}
+
+// Close implementa Encoder
+func (e *icalEncoder) Close() error {
+	e.begin()
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// begin escribe la cabecera del calendario una sola vez
+func (e *icalEncoder) begin() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + icalProdID)
+	e.line("CALSCALE:GREGORIAN")
+}
+
+// line escribe una línea de contenido plegándola cada 75 octetos sin partir caracteres UTF-8
+// El primer error de escritura se conserva y las escrituras siguientes se omiten.
+func (e *icalEncoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + icalNewline + icalFoldedPrefix); e.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = icalLineLimit - len(icalFoldedPrefix)
+	}
+	_, e.err = e.w.WriteString(s + icalNewline)
+}
+
+// icalEscape escapa un valor de texto (RFC 5545, sección 3.3.11)
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icalUnescape revierte icalEscape
+func icalUnescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
+
+// icalProperty línea de contenido ya desplegada: NOMBRE;PARAM=valor:valor
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICalProperty separa nombre, parámetros y valor; los ':' y ';' entre comillas pertenecen al parámetro
+func parseICalProperty(line string) (icalProperty, bool) {
+	prop := icalProperty{Params: map[string]string{}}
+	quoted := false
+	start := 0
+	var parts []string
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ';' || c == ':'):
+			parts = append(parts, line[start:i])
+			start = i + 1
+			if c == ':' {
+				prop.Value = line[start:]
+				prop.Name = strings.ToUpper(parts[0])
+				for _, param := range parts[1:] {
+					key, value, _ := strings.Cut(param, "=")
+					prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+				}
+				return prop, prop.Name != ""
+			}
+		}
+	}
+	return prop, false
+}
+
+// icalDecoder lee los componentes VTODO y VEVENT de un archivo iCalendar
+// Cada componente es una fila; los componentes anidados (ej. VALARM) y el resto se ignoran.
+type icalDecoder struct {
+	scanner     *bufio.Scanner
+	line        int    // Última línea física leída
+	pending     string // Línea lógica leída por adelantado al desplegar
+	pendingLine int
+	hasPending  bool
+}
+
+// newICalDecoder crea el decoder iCalendar
+func newICalDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxICalLineSize)
+	return &icalDecoder{scanner: scanner}
+}
+
+// next retorna la siguiente línea lógica (uniendo las líneas plegadas) y el número de su primera línea física
+func (d *icalDecoder) next() (string, int, error) {
+	if !d.hasPending {
+		if !d.scanner.Scan() {
+			if err := d.scanner.Err(); err != nil {
+				return "", 0, fmt.Errorf("invalid iCalendar after line %d: %w", d.line, err)
+			}
+			return "", 0, io.EOF
+		}
+		d.line++
+		d.pending, d.pendingLine, d.hasPending = strings.TrimRight(d.scanner.Text(), "\r"), d.line, true
+	}
+	line, number := d.pending, d.pendingLine
+	d.hasPending = false
+	for d.scanner.Scan() {
+		d.line++
+		text := strings.TrimRight(d.scanner.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line += text[1:]
+			continue
+		}
+		d.pending, d.pendingLine, d.hasPending = text, d.line, true
+		break
+	}
+	if err := d.scanner.Err(); err != nil {
+		return "", 0, fmt.Errorf("invalid iCalendar after line %d: %w", d.line, err)
+	}
+	return line, number, nil
+}
+
+// Next implementa Decoder
+func (d *icalDecoder) Next() (*Row, error) {
+	var component string // VTODO o VEVENT en lectura ("" fuera de ellos)
+	var props []icalProperty
+	var start, depth int
+	for {
+		line, number, err := d.next()
+		if err == io.EOF && component != "" {
+			return nil, fmt.Errorf("invalid iCalendar: %s starting at line %d is not closed", component, start)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, ok := parseICalProperty(line)
+		if !ok {
+			if component == "" {
+				continue
+			}
+			return &Row{Line: start, Err: models.ValidationErrors{{
+				Field: "row", Message: fmt.Sprintf("invalid content line %d", number),
+			}}}, d.skip(component)
+		}
+
+		value := strings.ToUpper(prop.Value)
+		switch {
+		case component == "" && prop.Name == "BEGIN" && (value == "VTODO" || value == "VEVENT"):
+			component, start, props = value, number, nil
+		case component == "":
+			// Fuera de un VTODO o VEVENT: VCALENDAR, VTIMEZONE, etc.
+		case prop.Name == "BEGIN":
+			depth++
+		case prop.Name == "END" && depth > 0:
+			depth--
+		case prop.Name == "END" && value == component:
+			return icalRow(component, start, props), nil
+		case depth == 0:
+			props = append(props, prop)
+		}
+	}
+}
+
+// skip descarta las líneas restantes del componente después de una línea inválida
+func (d *icalDecoder) skip(component string) error {
+	for {
+		line, _, err := d.next()
+		if err == io.EOF {
+			return nil // El error de la fila ya se informó
+		}
+		if err != nil {
+			return err
+		}
+		if prop, ok := parseICalProperty(line); ok && prop.Name == "END" && strings.EqualFold(prop.Value, component) {
+			return nil
+		}
+	}
+}
+
+// icalRow convierte las propiedades de un VTODO o VEVENT en los campos de la fila:
+//   - SUMMARY -> name, DESCRIPTION -> description
+//   - STATUS IN-PROCESS -> categoría active, COMPLETED -> categoría done (el resto toma el estado inicial del flujo)
+//   - PRIORITY 1-2 -> urgent, 3-4 -> high, 5 -> medium, 6-9 -> low
+//   - VTODO: DTSTART -> start_at, DUE -> due_at
+//   - VEVENT: DTEND (o DTSTART si no tiene) -> due_at, DTSTART -> start_at si es anterior a due_at
+func icalRow(component string, line int, props []icalProperty) *Row {
+	row := &Row{Line: line}
+	fields := map[string]interface{}{}
+	dates := map[string]time.Time{}
+	var errs models.ValidationErrors
+	for _, prop := range props {
+		switch prop.Name {
+		case "SUMMARY":
+			fields["name"] = strings.TrimSpace(icalUnescape(prop.Value))
+		case "DESCRIPTION":
+			fields["description"] = icalUnescape(prop.Value)
+		case "STATUS":
+			switch strings.ToUpper(prop.Value) {
+			case "IN-PROCESS":
+				row.Category = models.CategoryActive
+			case "COMPLETED":
+				row.Category = models.CategoryDone
+			}
+		case "PRIORITY":
+			if p := taskPriority(prop.Value); p != "" {
+				fields["priority"] = p
+			}
+		case "DTSTART", "DUE", "DTEND":
+			t, err := parseICalTime(prop)
+			if err != nil {
+				errs.Add(strings.ToLower(prop.Name), err.Error())
+				continue
+			}
+			dates[prop.Name] = t
+		}
+	}
+	if err := errs.Err(); err != nil {
+		row.Err = err
+		return row
+	}
+
+	if component == "VTODO" {
+		if t, ok := dates["DTSTART"]; ok {
+			fields["start_at"] = t
+		}
+		if t, ok := dates["DUE"]; ok {
+			fields["due_at"] = t
+		}
+	} else {
+		start, hasStart := dates["DTSTART"]
+		due, hasDue := dates["DTEND"]
+		if !hasDue && hasStart {
+			due, hasDue = start, true
+		}
+		if hasDue {
+			fields["due_at"] = due
+		}
+		if hasStart && start.Before(due) {
+			fields["start_at"] = start
+		}
+	}
+	row.Fields, _ = json.Marshal(fields)
+	return row
+}
+
+// parseICalTime interpreta un valor DATE o DATE-TIME; las horas locales usan la zona TZID si se conoce
+// y UTC en caso contrario
+func parseICalTime(prop icalProperty) (time.Time, error) {
+	value := strings.TrimSpace(prop.Value)
+	var t time.Time
+	var err error
+	switch {
+	case strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len(icalDate):
+		t, err = time.Parse(icalDate, value)
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(icalDateTime, value)
+	default:
+		loc := time.UTC
+		if tzid := prop.Params["TZID"]; tzid != "" {
+			if l, lerr := time.LoadLocation(tzid); lerr == nil {
+				loc = l
+			}
+		}
+		t, err = time.ParseInLocation(icalLocalTime, value, loc)
+	}
+	if err != nil {
+		return t, fmt.Errorf("invalid date %q", value)
+	}
+	return t, nil
+}
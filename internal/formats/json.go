@@ -0,0 +1,173 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// maxNDJSONLineSize tamaño máximo de una línea NDJSON
+const maxNDJSONLineSize = 1 << 20
+
+func init() {
+	Register(&Format{
+		Name:        "json",
+		ContentType: "application/json",
+		Extension:   "json",
+		NewEncoder:  func(w io.Writer) Encoder { return &jsonEncoder{w: bufio.NewWriter(w)} },
+		NewDecoder:  func(r io.Reader) Decoder { return &jsonDecoder{dec: json.NewDecoder(r)} },
+	})
+	Register(&Format{
+		Name:        "ndjson",
+		ContentType: "application/x-ndjson",
+		Extension:   "ndjson",
+		NewEncoder:  func(w io.Writer) Encoder { return &ndjsonEncoder{w: bufio.NewWriter(w)} },
+		NewDecoder:  newNDJSONDecoder,
+	})
+}
+
+// jsonEncoder escribe las tareas como un arreglo JSON, elemento por elemento
+type jsonEncoder struct {
+	w     *bufio.Writer
+	count int
+}
+
+// Encode implementa Encoder
+func (e *jsonEncoder) Encode(task *models.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+	if _, err := e.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// Close implementa Encoder
+func (e *jsonEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := e.w.WriteString(end); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// jsonDecoder lee un arreglo JSON de tareas sin cargarlo completo en memoria
+type jsonDecoder struct {
+	dec     *json.Decoder
+	started bool
+	line    int
+}
+
+// Next implementa Decoder
+func (d *jsonDecoder) Next() (*Row, error) {
+	if !d.started {
+		tok, err := d.dec.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, fmt.Errorf("invalid JSON: expected an array of tasks")
+		}
+		d.started = true
+	}
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil { // Cierre del arreglo
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return nil, io.EOF
+	}
+
+	d.line++
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON at element %d: %w", d.line, err)
+	}
+	return objectRow(d.line, raw), nil
+}
+
+// ndjsonEncoder escribe una tarea por línea
+type ndjsonEncoder struct {
+	w *bufio.Writer
+}
+
+// Encode implementa Encoder
+func (e *ndjsonEncoder) Encode(task *models.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+// Close implementa Encoder
+func (e *ndjsonEncoder) Close() error {
+	return e.w.Flush()
+}
+
+// ndjsonDecoder lee una tarea por línea; las líneas vacías se omiten
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// newNDJSONDecoder crea el decoder NDJSON
+func newNDJSONDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+// Next implementa Decoder
+func (d *ndjsonDecoder) Next() (*Row, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return objectRow(d.line, append(json.RawMessage(nil), line...)), nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid NDJSON after line %d: %w", d.line, err)
+	}
+	return nil, io.EOF
+}
+
+// objectRow construye la fila a partir de un objeto JSON, conservando solo los campos importables
+func objectRow(line int, raw json.RawMessage) *Row {
+	row := &Row{Line: line}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil || obj == nil {
+		row.Err = models.ValidationErrors{{Field: "row", Message: "row must be a JSON object"}}
+		return row
+	}
+	fields := make(map[string]json.RawMessage, len(obj))
+	for key, value := range obj {
+		if isImportField(key) {
+			fields[key] = value
+		}
+	}
+	row.Fields, _ = json.Marshal(fields)
+	return row
+}
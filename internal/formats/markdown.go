@@ -0,0 +1,272 @@
+package formats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// Marcas de la lista de tareas Markdown
+const (
+	markdownTitle     = "# Tasks"
+	markdownSection   = "## "    // Encabezado de sección: nombre del estado
+	markdownIndent    = "  "     // Sangría de las líneas de descripción
+	markdownTagPrefix = "#"      // Etiqueta al final del ítem: #backend
+	markdownPriority  = "!"      // Prioridad al final del ítem: !high
+	markdownChecked   = "- [x] " // Ítem de una tarea completada
+	markdownUnchecked = "- [ ] " // Ítem de una tarea pendiente
+)
+
+func init() {
+	Register(&Format{
+		Name:        "markdown",
+		ContentType: "text/markdown",
+		Extension:   "md",
+		NewEncoder:  func(w io.Writer) Encoder { return &markdownEncoder{w: bufio.NewWriter(w)} },
+		NewDecoder:  newMarkdownDecoder,
+	})
+}
+
+// markdownSectionTasks tareas de una sección (un estado)
+type markdownSectionTasks struct {
+	status   models.Status
+	category models.StatusCategory
+	tasks    []*models.Task
+}
+
+// markdownEncoder escribe una lista de tareas con una sección por estado:
+//
+//	## In progress
+//
+//	- [ ] Write report !high #docs #q4
+//	  Descripción, con sangría de dos espacios
+//
+// Las secciones se ordenan por categoría (todo, active, done) y, dentro de la misma categoría, por la
+// primera aparición del estado. Como las tareas se agrupan, se conservan en memoria hasta Close.
+type markdownEncoder struct {
+	w        *bufio.Writer
+	sections []*markdownSectionTasks
+}
+
+// Encode implementa Encoder
+func (e *markdownEncoder) Encode(task *models.Task) error {
+	for _, section := range e.sections {
+		if section.status == task.Status {
+			section.tasks = append(section.tasks, task)
+			return nil
+		}
+	}
+	e.sections = append(e.sections, &markdownSectionTasks{
+		status:   task.Status,
+		category: task.StatusCategory,
+		tasks:    []*models.Task{task},
+	})
+	return nil
+}
+
+// Close implementa Encoder
+func (e *markdownEncoder) Close() error {
+	fmt.Fprintln(e.w, markdownTitle)
+	for _, category := range []models.StatusCategory{models.CategoryTodo, models.CategoryActive, models.CategoryDone} {
+		for _, section := range e.sections {
+			if section.category == category {
+				e.writeSection(section)
+			}
+		}
+	}
+	// Estados sin categoría conocida, al final
+	for _, section := range e.sections {
+		if section.category.IsValid() != nil {
+			e.writeSection(section)
+		}
+	}
+	return e.w.Flush()
+}
+
+// writeSection escribe el encabezado de la sección y sus tareas
+func (e *markdownEncoder) writeSection(section *markdownSectionTasks) {
+	fmt.Fprintf(e.w, "\n%s%s\n\n", markdownSection, section.status)
+	for _, task := range section.tasks {
+		box := markdownUnchecked
+		if task.StatusCategory == models.CategoryDone {
+			box = markdownChecked
+		}
+		item := box + escapeItemName(task.Name) + " " + markdownPriority + string(task.Priority)
+		for _, tag := range task.Tags {
+			item += " " + markdownTagPrefix + tag.Name
+		}
+		fmt.Fprintln(e.w, item)
+		if task.Description != "" {
+			for _, line := range strings.Split(task.Description, "\n") {
+				fmt.Fprintln(e.w, strings.TrimRight(markdownIndent+escapeDescriptionLine(line), " "))
+			}
+		}
+	}
+}
+
+// escapeItemName antepone \ a las palabras del nombre que se leerían como etiqueta o prioridad
+func escapeItemName(name string) string {
+	words := strings.Split(name, " ")
+	for i, word := range words {
+		if strings.HasPrefix(word, markdownTagPrefix) || strings.HasPrefix(word, markdownPriority) || strings.HasPrefix(word, `\`) {
+			words[i] = `\` + word
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// escapeDescriptionLine antepone \ a una línea de la descripción que se leería como ítem (ver markdownDecoder)
+func escapeDescriptionLine(line string) string {
+	if _, _, isItem := parseMarkdownItem(line); isItem || strings.HasPrefix(line, `\`) {
+		return `\` + line
+	}
+	return line
+}
+
+// markdownDecoder lee una lista de tareas Markdown: cada ítem "- [ ]" o "- [x]" es una fila
+//   - El encabezado "## Estado" asigna el estado a los ítems de la sección; fuera de una sección,
+//     un ítem marcado se importa como Completed y uno sin marcar toma el estado inicial del flujo.
+//   - Las palabras finales !prioridad y #etiqueta se importan como prioridad y etiquetas.
+//   - Las líneas con sangría que siguen al ítem forman la descripción; un \ inicial se omite.
+//   - Los ítems anidados (con sangría) se importan como tareas independientes, sin jerarquía.
+//
+// El resto de líneas (título, párrafos, otros encabezados) se ignora.
+type markdownDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+	status  string // Estado de la sección actual ("" fuera de una sección)
+	pending *markdownItem
+}
+
+// markdownItem ítem en lectura (la descripción puede continuar en las líneas siguientes)
+type markdownItem struct {
+	line        int
+	indent      string // Sangría del ítem (los ítems anidados la tienen); su descripción lleva un nivel más
+	checked     bool
+	status      string
+	text        string
+	description []string
+	blanks      int // Líneas vacías pendientes dentro de la descripción
+}
+
+// newMarkdownDecoder crea el decoder Markdown
+func newMarkdownDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &markdownDecoder{scanner: scanner}
+}
+
+// Next implementa Decoder
+func (d *markdownDecoder) Next() (*Row, error) {
+	for d.scanner.Scan() {
+		d.line++
+		text := strings.TrimRight(d.scanner.Text(), " \t\r")
+
+		// Continuación de la descripción del ítem en lectura
+		if d.pending != nil {
+			if text == "" {
+				d.pending.blanks++
+				continue
+			}
+			_, _, nested := parseMarkdownItem(text)
+			inner, indented := strings.CutPrefix(text, d.pending.indent)
+			if !nested && indented && (strings.HasPrefix(inner, markdownIndent) || strings.HasPrefix(inner, "\t")) {
+				for ; d.pending.blanks > 0; d.pending.blanks-- {
+					d.pending.description = append(d.pending.description, "")
+				}
+				d.pending.description = append(d.pending.description, strings.TrimPrefix(trimIndent(inner), `\`))
+				continue
+			}
+		}
+
+		checked, rest, isItem := parseMarkdownItem(text)
+		if strings.HasPrefix(text, markdownSection) {
+			d.status = strings.TrimSpace(strings.TrimPrefix(text, markdownSection))
+		}
+		if !isItem && !strings.HasPrefix(text, "#") && d.pending == nil {
+			continue
+		}
+
+		// Una línea sin sangría o un ítem anidado termina el ítem en lectura
+		item := d.pending
+		d.pending = nil
+		if isItem {
+			indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+			d.pending = &markdownItem{line: d.line, indent: indent, checked: checked, status: d.status, text: rest}
+		}
+		if item != nil {
+			return item.row(), nil
+		}
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid Markdown after line %d: %w", d.line, err)
+	}
+	if d.pending != nil {
+		item := d.pending
+		d.pending = nil
+		return item.row(), nil
+	}
+	return nil, io.EOF
+}
+
+// parseMarkdownItem reconoce un ítem de lista de tareas ("- [ ] ", "* [x] ", "+ [X] ")
+// Retorna: si está marcado, el texto que sigue a la casilla y si la línea es un ítem
+func parseMarkdownItem(line string) (bool, string, bool) {
+	line = strings.TrimLeft(line, " \t")
+	if len(line) < 6 || !strings.ContainsRune("-*+", rune(line[0])) || line[1] != ' ' || line[2] != '[' || line[4] != ']' {
+		return false, "", false
+	}
+	switch line[3] {
+	case ' ':
+		return false, strings.TrimSpace(line[5:]), true
+	case 'x', 'X':
+		return true, strings.TrimSpace(line[5:]), true
+	}
+	return false, "", false
+}
+
+// trimIndent quita un nivel de sangría (dos espacios o una tabulación)
+func trimIndent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	return strings.TrimPrefix(line, markdownIndent)
+}
+
+// row convierte el ítem en la fila de importación
+func (item *markdownItem) row() *Row {
+	row := &Row{Line: item.line, Tags: []string{}}
+	fields := map[string]interface{}{}
+
+	words := strings.Fields(item.text)
+	end := len(words)
+	for end > 0 {
+		word := words[end-1]
+		if strings.HasPrefix(word, markdownTagPrefix) && len(word) > 1 {
+			row.Tags = append([]string{word[1:]}, row.Tags...)
+		} else if strings.HasPrefix(word, markdownPriority) && models.Priority(word[1:]).IsValid() == nil {
+			fields["priority"] = word[1:]
+		} else {
+			break
+		}
+		end--
+	}
+	for i := range words[:end] {
+		words[i] = strings.TrimPrefix(words[i], `\`)
+	}
+	fields["name"] = strings.Join(words[:end], " ")
+	fields["description"] = strings.Join(item.description, "\n")
+
+	switch {
+	case item.status != "":
+		fields["status"] = item.status
+	case item.checked:
+		fields["status"] = models.Completed
+	}
+	row.Fields, _ = json.Marshal(fields)
+	return row
+}
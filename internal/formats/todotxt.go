@@ -0,0 +1,251 @@
+package formats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// Claves key:value de todo.txt que se leen y escriben; cualquier otra palabra con ':' forma parte del nombre
+const (
+	todoDueKey         = "due"    // Fecha límite (extensión habitual de todo.txt)
+	todoThresholdKey   = "t"      // Fecha de inicio (extensión habitual "threshold")
+	todoStatusKey      = "status" // Estado distinto de To do y Completed, con codificación URL
+	todoDescriptionKey = "desc"   // Descripción, con codificación URL (todo.txt es de una línea por tarea)
+	todoPriorityKey    = "pri"    // Prioridad de una tarea completada (las completadas no llevan "(A)")
+	todoDate           = "2006-01-02"
+)
+
+// todoPriorities prioridad todo.txt de cada prioridad de tarea
+var todoPriorities = map[models.Priority]string{
+	models.PriorityUrgent: "A",
+	models.PriorityHigh:   "B",
+	models.PriorityMedium: "C",
+	models.PriorityLow:    "D",
+}
+
+// todoPriorityPattern prioridad al inicio de la línea: "(A) "
+var todoPriorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+func init() {
+	Register(&Format{
+		Name:        "todotxt",
+		ContentType: "text/plain",
+		Extension:   "txt",
+		NewEncoder:  func(w io.Writer) Encoder { return &todoEncoder{w: bufio.NewWriter(w)} },
+		NewDecoder:  newTodoDecoder,
+	})
+}
+
+// todoEncoder escribe una tarea por línea en formato todo.txt:
+//
+//	(B) 2026-10-01 Write report +docs due:2026-12-01 desc:Primer%20borrador
+//	x 2026-10-20 2026-10-01 Ship release +backend pri:A
+//
+// Las etiquetas se escriben como +etiqueta; la fecha de creación es la de la tarea y, en las
+// completadas, la fecha de completado es la de su última modificación.
+type todoEncoder struct {
+	w *bufio.Writer
+}
+
+// Encode implementa Encoder
+func (e *todoEncoder) Encode(task *models.Task) error {
+	var parts []string
+	done := task.StatusCategory == models.CategoryDone
+	if done {
+		parts = append(parts, "x", task.UpdatedAt.UTC().Format(todoDate))
+	} else if p, ok := todoPriorities[task.Priority]; ok {
+		parts = append(parts, "("+p+")")
+	}
+	parts = append(parts, task.CreatedAt.UTC().Format(todoDate), escapeTodoName(task.Name))
+	for _, tag := range task.Tags {
+		parts = append(parts, "+"+tag.Name)
+	}
+	if task.DueAt != nil {
+		parts = append(parts, todoDueKey+":"+task.DueAt.UTC().Format(todoDate))
+	}
+	if task.StartAt != nil {
+		parts = append(parts, todoThresholdKey+":"+task.StartAt.UTC().Format(todoDate))
+	}
+	if task.Status != models.ToDo && task.Status != models.Completed {
+		parts = append(parts, todoStatusKey+":"+url.PathEscape(string(task.Status)))
+	}
+	if done {
+		if p, ok := todoPriorities[task.Priority]; ok {
+			parts = append(parts, todoPriorityKey+":"+p)
+		}
+	}
+	if task.Description != "" {
+		parts = append(parts, todoDescriptionKey+":"+url.PathEscape(task.Description))
+	}
+	_, err := e.w.WriteString(strings.Join(parts, " ") + "\n")
+	return err
+}
+
+// Close implementa Encoder
+func (e *todoEncoder) Close() error {
+	return e.w.Flush()
+}
+
+// escapeTodoName antepone \ a las palabras del nombre que se leerían como etiqueta o clave key:value y,
+// si es la primera, como marca de completada, prioridad o fecha (ver todoDecoder)
+func escapeTodoName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		if isTodoMarker(word) || (i == 0 && (word == "x" || todoPriorityPattern.MatchString(word) || isTodoDate(word))) {
+			words[i] = `\` + word
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// isTodoMarker indica si la palabra se leería como etiqueta o clave conocida, o ya empieza con \
+func isTodoMarker(word string) bool {
+	if strings.HasPrefix(word, `\`) || ((strings.HasPrefix(word, "+") || strings.HasPrefix(word, "@")) && len(word) > 1) {
+		return true
+	}
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || value == "" {
+		return false
+	}
+	switch key {
+	case todoDueKey, todoThresholdKey, todoStatusKey, todoDescriptionKey, todoPriorityKey:
+		return true
+	}
+	return false
+}
+
+// todoDecoder lee un archivo todo.txt; cada línea no vacía es una fila
+//   - "x" al inicio marca la tarea como Completed (salvo que status: indique otro estado)
+//   - (A) urgent, (B) high, (C) medium, (D)-(Z) low; pri: en las tareas completadas
+//   - +palabra y @palabra se importan como etiquetas
+//   - due:, t:, status: y desc: como fecha límite, fecha de inicio, estado y descripción
+//   - una palabra que empieza con \ forma parte del nombre, sin la \ (ver escapeTodoName)
+type todoDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// newTodoDecoder crea el decoder todo.txt
+func newTodoDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &todoDecoder{scanner: scanner}
+}
+
+// Next implementa Decoder
+func (d *todoDecoder) Next() (*Row, error) {
+	for d.scanner.Scan() {
+		d.line++
+		if text := strings.TrimSpace(d.scanner.Text()); text != "" {
+			return todoRow(d.line, text), nil
+		}
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid todo.txt after line %d: %w", d.line, err)
+	}
+	return nil, io.EOF
+}
+
+// todoRow convierte una línea todo.txt en la fila de importación
+func todoRow(line int, text string) *Row {
+	row := &Row{Line: line, Tags: []string{}}
+	fields := map[string]interface{}{}
+	var errs models.ValidationErrors
+	words := strings.Fields(text)
+
+	// Prefijo: "x [fecha de completado] [fecha de creación]" o "(A) [fecha de creación]"
+	if words[0] == "x" {
+		fields["status"] = models.Completed
+		words = words[1:]
+		for i := 0; i < 2 && len(words) > 0 && isTodoDate(words[0]); i++ {
+			words = words[1:]
+		}
+	} else {
+		if m := todoPriorityPattern.FindStringSubmatch(words[0]); m != nil {
+			fields["priority"] = todoPriority(m[1])
+			words = words[1:]
+		}
+		if len(words) > 0 && isTodoDate(words[0]) {
+			words = words[1:]
+		}
+	}
+
+	var name []string
+	for _, word := range words {
+		if strings.HasPrefix(word, `\`) {
+			name = append(name, word[1:])
+			continue
+		}
+		if (strings.HasPrefix(word, "+") || strings.HasPrefix(word, "@")) && len(word) > 1 {
+			row.Tags = append(row.Tags, word[1:])
+			continue
+		}
+		key, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			name = append(name, word)
+			continue
+		}
+		switch key {
+		case todoDueKey, todoThresholdKey:
+			field := "due_at"
+			if key == todoThresholdKey {
+				field = "start_at"
+			}
+			t, err := time.Parse(todoDate, value)
+			if err != nil {
+				errs.Add(field, fmt.Sprintf("%s: must be a YYYY-MM-DD date", key))
+				continue
+			}
+			fields[field] = t
+		case todoStatusKey, todoDescriptionKey:
+			decoded, err := url.PathUnescape(value)
+			if err != nil {
+				errs.Add(key, fmt.Sprintf("%s: invalid URL encoding", key))
+				continue
+			}
+			if key == todoStatusKey {
+				fields["status"] = decoded
+			} else {
+				fields["description"] = decoded
+			}
+		case todoPriorityKey:
+			fields["priority"] = todoPriority(value)
+		default:
+			name = append(name, word)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		row.Err = err
+		return row
+	}
+	fields["name"] = strings.Join(name, " ")
+	if _, ok := fields["description"]; !ok {
+		fields["description"] = ""
+	}
+	row.Fields, _ = json.Marshal(fields)
+	return row
+}
+
+// isTodoDate indica si la palabra es una fecha YYYY-MM-DD
+func isTodoDate(word string) bool {
+	_, err := time.Parse(todoDate, word)
+	return err == nil
+}
+
+// todoPriority convierte una prioridad todo.txt (A-Z) en la de la tarea
+func todoPriority(letter string) models.Priority {
+	for priority, l := range todoPriorities {
+		if l == letter {
+			return priority
+		}
+	}
+	return models.PriorityLow
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// ndjsonContentType tipo de contenido de la exportación de la auditoría (un objeto JSON por línea)
+const ndjsonContentType = "application/x-ndjson"
+
+// AuditHandler define los handlers HTTP de la auditoría de tareas
+type AuditHandler interface {
+	GetAuditHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// auditHandler implementación de AuditHandler
+type auditHandler struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditHandler crea un nuevo AuditHandler con el repositorio proporcionado.
+func NewAuditHandler(repo repository.AuditRepository) AuditHandler {
+	return &auditHandler{repo: repo}
+}
+
+// auditListResponse cuerpo de respuesta de GET /audit
+type auditListResponse struct {
+	Items  []models.AuditEntry `json:"items"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+// GetAuditHandler consulta la auditoría de tareas en orden cronológico.
+// Filtros: task_id, actor y since (RFC 3339 o YYYY-MM-DD); paginación con limit y offset.
+// Con format=ndjson o Accept: application/x-ndjson exporta todos los registros que cumplen
+// los filtros, uno por línea, ignorando la paginación.
+// Método HTTP: GET
+// Ruta: /audit
+func (h *auditHandler) GetAuditHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAuditQuery(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+		return
+	}
+
+	if wantsNDJSON(r) {
+		h.exportAudit(w, r, query)
+		return
+	}
+
+	page, err := h.repo.GetAuditEntries(r.Context(), query)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, auditListResponse{Items: page.Entries, Limit: page.Limit, Offset: page.Offset})
+}
+
+// exportAudit escribe los registros como NDJSON a medida que se leen de la base de datos
+// Un error posterior al primer registro ya no puede informarse con un código HTTP, por lo que solo se registra en el log.
+func (h *auditHandler) exportAudit(w http.ResponseWriter, r *http.Request, query repository.AuditQuery) {
+	encoder := json.NewEncoder(w)
+	started := false
+	err := h.repo.ExportAuditEntries(r.Context(), query, func(entry *models.AuditEntry) error {
+		if !started {
+			w.Header().Set("Content-Type", ndjsonContentType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		return encoder.Encode(entry)
+	})
+	if err != nil && !started {
+		writeDomainError(w, r, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Error exporting audit entries: %v", err)
+		return
+	}
+	if !started {
+		// Sin registros: respuesta vacía con el tipo de contenido correcto
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// wantsNDJSON indica si el cliente pidió la exportación NDJSON (parámetro format o header Accept)
+func wantsNDJSON(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "ndjson"
+	}
+	return strings.Contains(r.Header.Get("Accept"), ndjsonContentType)
+}
+
+// parseAuditQuery convierte los parámetros task_id, actor, since, limit y offset en filtros de auditoría
+func parseAuditQuery(r *http.Request) (repository.AuditQuery, error) {
+	q := r.URL.Query()
+	var query repository.AuditQuery
+	var err error
+
+	if raw := q.Get("task_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil || id == 0 {
+			return query, fmt.Errorf("invalid task_id: expected a positive integer")
+		}
+		taskID := uint(id)
+		query.TaskID = &taskID
+	}
+	if format := q.Get("format"); format != "" && format != "json" && format != "ndjson" {
+		return query, fmt.Errorf("invalid format: expected json or ndjson")
+	}
+	query.Actor = strings.TrimSpace(q.Get("actor"))
+	if query.Since, err = parseTimeParam(q.Get("since"), "since"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = parseIntParam(q.Get("limit"), "limit"); err != nil {
+		return query, err
+	}
+	if query.Offset, err = parseIntParam(q.Get("offset"), "offset"); err != nil {
+		return query, err
+	}
+	return query, nil
+}
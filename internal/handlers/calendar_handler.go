@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/formats"
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// Códigos de error de los feeds iCalendar
+const (
+	CodeInvalidToken = "invalid_token" // El token de la URL del calendario falta o no corresponde al feed
+	CodeUnauthorized = "unauthorized"  // Falta el token de administración de los feeds o no es válido
+)
+
+// calendarTokenParam parámetro de la URL con el token del feed
+const calendarTokenParam = "token"
+
+// calendarScopeAll alcance del token del feed con todas las tareas
+const calendarScopeAll = "all"
+
+// CalendarOptions configuración de los feeds iCalendar
+type CalendarOptions struct {
+	Secret         string         // Secreto con el que se firman los tokens de los feeds (vacío = feeds desactivados)
+	AdminToken     string         // Token Bearer para consultar y renovar las URLs de los feeds (vacío = no se exponen)
+	TrustedProxies []netip.Prefix // Proxies cuyo header X-Forwarded-Proto se respeta al construir las URLs
+}
+
+// CalendarHandler define los handlers de los feeds iCalendar.
+// Las aplicaciones de calendario no pueden enviar headers, por lo que cada feed se protege con un
+// token en la URL derivado (HMAC-SHA256) del secreto configurado, del alcance del feed y de su versión:
+// el token de un proyecto no da acceso al feed general ni al de otro proyecto, y renovar un feed
+// (nueva versión) invalida sus URLs anteriores. Las URLs solo se obtienen con el token de administración.
+type CalendarHandler interface {
+	GetCalendarHandler(w http.ResponseWriter, r *http.Request)        // Feed con todas las tareas.
+	GetProjectCalendarHandler(w http.ResponseWriter, r *http.Request) // Feed con las tareas de un proyecto.
+	GetCalendarFeedsHandler(w http.ResponseWriter, r *http.Request)   // URLs de los feeds con sus tokens.
+	RotateCalendarFeedHandler(w http.ResponseWriter, r *http.Request) // Renueva el token de un feed.
+}
+
+// calendarHandler implementación de CalendarHandler
+type calendarHandler struct {
+	tasks    repository.TaskRepository
+	projects repository.ProjectRepository      // nil si el backend de almacenamiento no tiene proyectos
+	feeds    repository.CalendarFeedRepository // nil si el backend no guarda versiones: todos los feeds están en la versión 1
+	opts     CalendarOptions
+}
+
+// NewCalendarHandler crea el handler de los feeds iCalendar
+// Recibe: repositorios de tareas, proyectos (nil sin proyectos: solo el feed general) y versiones de los feeds
+// (nil si no se pueden renovar), y la configuración de los feeds
+func NewCalendarHandler(tasks repository.TaskRepository, projects repository.ProjectRepository,
+	feeds repository.CalendarFeedRepository, opts CalendarOptions) CalendarHandler {
+	return &calendarHandler{tasks: tasks, projects: projects, feeds: feeds, opts: opts}
+}
+
+// calendarFeed URL de un feed
+type calendarFeed struct {
+	ProjectID *uint  `json:"project_id,omitempty"`
+	Name      string `json:"name"`
+	Version   uint   `json:"version"` // Versión del token; aumenta cada vez que se renueva
+	URL       string `json:"url"`
+}
+
+// calendarFeedsResponse cuerpo de respuesta de GET /calendar/feeds
+type calendarFeedsResponse struct {
+	Items []calendarFeed `json:"items"` // Feed general seguido de un feed por proyecto activo
+}
+
+// GetCalendarHandler maneja el feed iCalendar con todas las tareas como componentes VTODO.
+// Método HTTP: GET
+// Ruta: /calendar.ics?token= (admite los filtros de /tasks)
+func (h *calendarHandler) GetCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, calendarScopeAll) {
+		return
+	}
+	h.writeFeed(w, r, nil)
+}
+
+// GetProjectCalendarHandler maneja el feed iCalendar con las tareas de un proyecto.
+// Método HTTP: GET
+// Ruta: /projects/{pid}/calendar.ics?token= (admite los filtros de /tasks)
+func (h *calendarHandler) GetProjectCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	if !h.authorize(w, r, projectScope(projectID)) {
+		return
+	}
+	if _, err := h.projects.GetProjectByID(r.Context(), projectID); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	h.writeFeed(w, r, &projectID)
+}
+
+// GetCalendarFeedsHandler maneja la obtención de las URLs de los feeds, ya con su token,
+// para suscribirse desde una aplicación de calendario. Requiere el token de administración.
+// Método HTTP: GET
+// Ruta: /calendar/feeds
+func (h *calendarHandler) GetCalendarFeedsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	versions, err := h.feedVersions(r)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	var projects []models.Project
+	if h.projects != nil {
+		if projects, err = h.projects.GetProjects(r.Context(), false); err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+	}
+	resp := calendarFeedsResponse{Items: []calendarFeed{h.feed(r, nil, "All tasks", versions)}}
+	for _, project := range projects {
+		id := project.ID
+		resp.Items = append(resp.Items, h.feed(r, &id, project.Name, versions))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// RotateCalendarFeedHandler maneja la renovación del token de un feed: sus URLs anteriores dejan de ser
+// válidas y la respuesta incluye la nueva. Requiere el token de administración.
+// Método HTTP: POST
+// Ruta: /calendar/feeds/rotate?project_id= (sin project_id, el feed general)
+func (h *calendarHandler) RotateCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	name, scope := "All tasks", calendarScopeAll
+	var projectID *uint
+	if raw := r.URL.Query().Get("project_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil || id == 0 || h.projects == nil {
+			writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, "project_id must be a positive integer")
+			return
+		}
+		project, err := h.projects.GetProjectByID(r.Context(), uint(id))
+		if err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+		projectID, name, scope = &project.ID, project.Name, projectScope(project.ID)
+	}
+	version, err := h.feeds.RotateFeed(r.Context(), scope)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.feed(r, projectID, name, map[string]uint{scope: version}))
+}
+
+// feed construye la URL del feed general (projectID nil) o de un proyecto con la versión vigente de su token
+func (h *calendarHandler) feed(r *http.Request, projectID *uint, name string, versions map[string]uint) calendarFeed {
+	path, scope := "/calendar.ics", calendarScopeAll
+	if projectID != nil {
+		path, scope = fmt.Sprintf("/projects/%d/calendar.ics", *projectID), projectScope(*projectID)
+	}
+	version := feedVersion(versions, scope)
+	return calendarFeed{ProjectID: projectID, Name: name, Version: version, URL: h.feedURL(r, path, scope, version)}
+}
+
+// writeFeed escribe las tareas que cumplen los filtros como un VCALENDAR
+func (h *calendarHandler) writeFeed(w http.ResponseWriter, r *http.Request, projectID *uint) {
+	format, _ := formats.Lookup("ics")
+	opts, err := parseTaskQuery(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+		return
+	}
+	opts.ProjectID = projectID
+	opts.Limit, opts.Offset, opts.Cursor = repository.MaxPageSize, 0, ""
+
+	page, err := h.tasks.GetTasks(r.Context(), opts)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := exportTasks(r.Context(), h.tasks, opts, page, format.NewEncoder(w)); err != nil {
+		log.Printf("Error writing calendar feed: %v", err)
+	}
+}
+
+// authorize verifica el token del feed; responde 404 si los feeds están desactivados y 403 si el token no es válido
+func (h *calendarHandler) authorize(w http.ResponseWriter, r *http.Request, scope string) bool {
+	if h.opts.Secret == "" {
+		writeError(w, r, http.StatusNotFound, CodeNotFound, "Calendar feeds are disabled")
+		return false
+	}
+	token := r.URL.Query().Get(calendarTokenParam)
+	if token == "" {
+		writeError(w, r, http.StatusForbidden, CodeInvalidToken, "Missing or invalid calendar token")
+		return false
+	}
+	version, err := h.scopeVersion(r, scope)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return false
+	}
+	if !hmac.Equal([]byte(token), []byte(h.token(scope, version))) {
+		writeError(w, r, http.StatusForbidden, CodeInvalidToken, "Missing or invalid calendar token")
+		return false
+	}
+	return true
+}
+
+// authorizeAdmin verifica el token de administración (header Authorization: Bearer);
+// responde 404 si los feeds o su administración están desactivados y 401 si el token no es válido
+func (h *calendarHandler) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.opts.Secret == "" || h.opts.AdminToken == "" {
+		writeError(w, r, http.StatusNotFound, CodeNotFound, "Calendar feed management is disabled")
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !hmac.Equal([]byte(token), []byte(h.opts.AdminToken)) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+		writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, "Missing or invalid calendar admin token")
+		return false
+	}
+	return true
+}
+
+// feedVersions obtiene la versión vigente de los feeds renovados (vacío si el backend no guarda versiones)
+func (h *calendarHandler) feedVersions(r *http.Request) (map[string]uint, error) {
+	if h.feeds == nil {
+		return nil, nil
+	}
+	return h.feeds.GetFeedVersions(r.Context())
+}
+
+// scopeVersion obtiene la versión vigente de un solo feed (1 si el backend no guarda versiones)
+func (h *calendarHandler) scopeVersion(r *http.Request, scope string) (uint, error) {
+	if h.feeds == nil {
+		return 1, nil
+	}
+	return h.feeds.GetFeedVersion(r.Context(), scope)
+}
+
+// feedVersion versión vigente del feed; un feed que nunca se renovó está en la versión 1
+func feedVersion(versions map[string]uint, scope string) uint {
+	if v, ok := versions[scope]; ok {
+		return v
+	}
+	return 1
+}
+
+// token calcula el token del feed con el alcance y la versión indicados
+func (h *calendarHandler) token(scope string, version uint) string {
+	mac := hmac.New(sha256.New, []byte(h.opts.Secret))
+	fmt.Fprintf(mac, "calendar:%s:%d", scope, version)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// feedURL construye la URL absoluta del feed a partir del host de la solicitud
+// El header X-Forwarded-Proto solo se respeta si la solicitud llega de un proxy de confianza.
+func (h *calendarHandler) feedURL(r *http.Request, path, scope string, version uint) string {
+	scheme := "http"
+	if r.TLS != nil || (h.fromTrustedProxy(r) && r.Header.Get("X-Forwarded-Proto") == "https") {
+		scheme = "https"
+	}
+	query := url.Values{calendarTokenParam: {h.token(scope, version)}}
+	u := url.URL{Scheme: scheme, Host: r.Host, Path: path, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// fromTrustedProxy indica si la conexión proviene de uno de los proxies de confianza configurados
+func (h *calendarHandler) fromTrustedProxy(r *http.Request) bool {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range h.opts.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectScope alcance del token del feed de un proyecto
+func projectScope(id uint) string {
+	return fmt.Sprintf("project:%d", id)
+}
@@ -0,0 +1,151 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/handlers"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// fakeFeedRepository versiones de los feeds en memoria
+type fakeFeedRepository map[string]uint
+
+func (f fakeFeedRepository) GetFeedVersions(ctx context.Context) (map[string]uint, error) {
+	return f, nil
+}
+
+func (f fakeFeedRepository) GetFeedVersion(ctx context.Context, scope string) (uint, error) {
+	if v, ok := f[scope]; ok {
+		return v, nil
+	}
+	return 1, nil
+}
+
+func (f fakeFeedRepository) RotateFeed(ctx context.Context, scope string) (uint, error) {
+	if f[scope] == 0 {
+		f[scope] = 1
+	}
+	f[scope]++
+	return f[scope], nil
+}
+
+// serveCalendar ejecuta la solicitud contra las rutas de calendar_handler.go, registradas como en routes.SetupRoutes
+// La solicitud llega desde 192.0.2.1 (httptest); los headers se indican como pares nombre, valor.
+func serveCalendar(t *testing.T, feeds fakeFeedRepository, opts handlers.CalendarOptions, method, target string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	repo := &fakeTaskRepository{t: t, getTasks: func(repository.TaskQueryOptions) (*repository.TaskPage, error) {
+		return &repository.TaskPage{}, nil
+	}}
+	h := handlers.NewCalendarHandler(repo, nil, feeds, opts)
+
+	r := chi.NewRouter()
+	r.Get("/calendar.ics", h.GetCalendarHandler)
+	r.Get("/calendar/feeds", h.GetCalendarFeedsHandler)
+	r.Post("/calendar/feeds/rotate", h.RotateCalendarFeedHandler)
+
+	req := httptest.NewRequest(method, target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+// calendarFeed URL de un feed en las respuestas de GET /calendar/feeds y POST /calendar/feeds/rotate
+type calendarFeed struct {
+	Version uint   `json:"version"`
+	URL     string `json:"url"`
+}
+
+// calendarFeeds cuerpo de GET /calendar/feeds
+type calendarFeeds struct {
+	Items []calendarFeed `json:"items"`
+}
+
+// requestURI ruta y query de la URL de un feed, para solicitarla con serveCalendar
+func requestURI(t *testing.T, raw string) string {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.RequestURI()
+}
+
+func TestGetCalendarFeedsHandlerRequiresAdminToken(t *testing.T) {
+	opts := handlers.CalendarOptions{Secret: "secret", AdminToken: "admin"}
+	expectProblem(t, serveCalendar(t, fakeFeedRepository{}, opts, http.MethodGet, "/calendar/feeds"),
+		http.StatusUnauthorized, handlers.CodeUnauthorized)
+	expectProblem(t, serveCalendar(t, fakeFeedRepository{}, opts, http.MethodGet, "/calendar/feeds", "Authorization", "Bearer wrong"),
+		http.StatusUnauthorized, handlers.CodeUnauthorized)
+	expectProblem(t, serveCalendar(t, fakeFeedRepository{}, handlers.CalendarOptions{Secret: "secret"}, http.MethodGet, "/calendar/feeds"),
+		http.StatusNotFound, handlers.CodeNotFound)
+
+	rec := serveCalendar(t, fakeFeedRepository{}, opts, http.MethodGet, "/calendar/feeds",
+		"Authorization", "Bearer admin", "X-Forwarded-Proto", "https")
+	var feeds calendarFeeds
+	decode(t, rec, &feeds)
+	if rec.Code != http.StatusOK || len(feeds.Items) != 1 || !strings.HasPrefix(feeds.Items[0].URL, "http://") {
+		t.Errorf("feeds = %d %+v, want one http URL (X-Forwarded-Proto from an untrusted client)", rec.Code, feeds)
+	}
+
+	opts.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}
+	rec = serveCalendar(t, fakeFeedRepository{}, opts, http.MethodGet, "/calendar/feeds",
+		"Authorization", "Bearer admin", "X-Forwarded-Proto", "https")
+	decode(t, rec, &feeds)
+	if !strings.HasPrefix(feeds.Items[0].URL, "https://") {
+		t.Errorf("feed URL = %s, want https behind a trusted proxy", feeds.Items[0].URL)
+	}
+}
+
+func TestRotateCalendarFeedHandler(t *testing.T) {
+	opts := handlers.CalendarOptions{Secret: "secret", AdminToken: "admin"}
+	versions := fakeFeedRepository{}
+	var feeds calendarFeeds
+	decode(t, serveCalendar(t, versions, opts, http.MethodGet, "/calendar/feeds", "Authorization", "Bearer admin"), &feeds)
+	oldURL := requestURI(t, feeds.Items[0].URL)
+	if rec := serveCalendar(t, versions, opts, http.MethodGet, oldURL); rec.Code != http.StatusOK {
+		t.Fatalf("feed with a valid token: status %d", rec.Code)
+	}
+
+	expectProblem(t, serveCalendar(t, versions, opts, http.MethodPost, "/calendar/feeds/rotate"),
+		http.StatusUnauthorized, handlers.CodeUnauthorized)
+	var rotated calendarFeed
+	decode(t, serveCalendar(t, versions, opts, http.MethodPost, "/calendar/feeds/rotate", "Authorization", "Bearer admin"), &rotated)
+	newURL := requestURI(t, rotated.URL)
+	if rotated.Version != 2 || newURL == oldURL {
+		t.Fatalf("rotated feed = %+v, want version 2 with a new token", rotated)
+	}
+	expectProblem(t, serveCalendar(t, versions, opts, http.MethodGet, oldURL), http.StatusForbidden, handlers.CodeInvalidToken)
+	if rec := serveCalendar(t, versions, opts, http.MethodGet, newURL); rec.Code != http.StatusOK {
+		t.Errorf("feed with the rotated token: status %d", rec.Code)
+	}
+}
+
+func TestLoggerRedactsCalendarToken(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := handlers.RedactingLogFormatter(&middleware.DefaultLogFormatter{Logger: log.New(&buf, "", 0), NoColor: true})
+	var received string
+	handler := middleware.RequestLogger(formatter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query().Get("token")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar.ics?status=todo&token=s3cret", nil))
+
+	if strings.Contains(buf.String(), "s3cret") || !strings.Contains(buf.String(), "token=REDACTED") {
+		t.Errorf("log = %q, want the token redacted", buf.String())
+	}
+	if received != "s3cret" {
+		t.Errorf("handler received token %q, want the original", received)
+	}
+}
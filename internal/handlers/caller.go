@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ActorHeader header opcional con el autor de la solicitud, registrado en el historial de estados y la auditoría
+const ActorHeader = "X-Actor"
+
+// maxActorLength límite de longitud del autor y del ID de la solicitud, debe coincidir con la etiqueta size de los modelos
+const maxActorLength = 100
+
+// requestCaller identifica el origen de la solicitud: autor del header X-Actor ("" si no se indica)
+// e ID asignado por el middleware RequestID, que conserva el header X-Request-Id del cliente
+// Ambos se recortan a maxActorLength para que un valor largo no impida guardar la auditoría.
+func requestCaller(r *http.Request) repository.Caller {
+	return repository.Caller{
+		Actor:     truncate(strings.TrimSpace(r.Header.Get(ActorHeader)), maxActorLength),
+		RequestID: truncate(middleware.GetReqID(r.Context()), maxActorLength),
+	}
+}
+
+// truncate recorta el texto a n caracteres
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) > n {
+		return string([]rune(s)[:n])
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// DependencyHandler define los handlers HTTP de las dependencias entre tareas ("A bloquea a B")
+type DependencyHandler interface {
+	GetTaskDependenciesHandler(w http.ResponseWriter, r *http.Request)
+	AddTaskBlockerHandler(w http.ResponseWriter, r *http.Request)
+	RemoveTaskBlockerHandler(w http.ResponseWriter, r *http.Request)
+	GetPlanHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// dependencyHandler implementación de DependencyHandler
+type dependencyHandler struct {
+	deps  repository.DependencyRepository
+	tasks repository.TaskRepository // Para devolver la tarea actualizada al agregar o quitar bloqueadores
+}
+
+// NewDependencyHandler crea un nuevo DependencyHandler con los repositorios proporcionados.
+func NewDependencyHandler(deps repository.DependencyRepository, tasks repository.TaskRepository) DependencyHandler {
+	return &dependencyHandler{deps: deps, tasks: tasks}
+}
+
+// planResponse cuerpo de respuesta de GET /tasks/plan
+type planResponse struct {
+	Items []models.Task `json:"items"`
+}
+
+// GetTaskDependenciesHandler obtiene las tareas que bloquean a una tarea y las que ella bloquea.
+// Método HTTP: GET
+// Ruta: /tasks/{id}/dependencies
+func (h *dependencyHandler) GetTaskDependenciesHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	deps, err := h.deps.GetDependencies(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deps)
+}
+
+// AddTaskBlockerHandler registra que la tarea {blocker} bloquea a la tarea {id}.
+// Responde 409 si la dependencia generaría un ciclo.
+// Método HTTP: POST
+// Ruta: /tasks/{id}/blockers/{blocker}
+func (h *dependencyHandler) AddTaskBlockerHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeBlocker(w, r, h.deps.AddDependency)
+}
+
+// RemoveTaskBlockerHandler elimina la dependencia entre la tarea {blocker} y la tarea {id}.
+// Método HTTP: DELETE
+// Ruta: /tasks/{id}/blockers/{blocker}
+func (h *dependencyHandler) RemoveTaskBlockerHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeBlocker(w, r, h.deps.RemoveDependency)
+}
+
+// changeBlocker aplica el cambio de dependencia y responde con la tarea bloqueada y su nuevo ETag
+func (h *dependencyHandler) changeBlocker(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, blockerID, blockedID uint) error) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	blocker, err := strconv.ParseUint(chi.URLParam(r, "blocker"), 10, 0)
+	if err != nil || blocker == 0 {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidID, "Invalid blocker task ID")
+		return
+	}
+	if err := change(r.Context(), uint(blocker), id); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	task, err := h.tasks.GetTaskByID(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", taskETag(task))
+	writeJSON(w, http.StatusOK, task)
+}
+
+// GetPlanHandler obtiene las tareas abiertas en orden topológico (los bloqueadores primero).
+// Método HTTP: GET
+// Ruta: /tasks/plan?project_id=
+func (h *dependencyHandler) GetPlanHandler(w http.ResponseWriter, r *http.Request) {
+	var projectID *uint
+	if raw := r.URL.Query().Get("project_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil || id == 0 {
+			writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, "project_id must be a positive integer")
+			return
+		}
+		pid := uint(id)
+		projectID = &pid
+	}
+	tasks, err := h.deps.GetPlan(r.Context(), projectID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, planResponse{Items: tasks})
+}
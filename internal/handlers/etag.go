@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// CodePreconditionFailed la tarea cambió desde que el cliente obtuvo su ETag
+const CodePreconditionFailed = "precondition_failed"
+
+// taskETag ETag fuerte de una tarea, derivado de su versión
+func taskETag(t *models.Task) string {
+	return fmt.Sprintf(`"%d"`, t.Version)
+}
+
+// listETag ETag débil de una página de tareas, derivado de los IDs y versiones incluidos.
+// Cambia cuando se crea, modifica o elimina cualquier tarea de la página.
+func listETag(tasks []models.Task, total int64, nextCursor string) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d|%s", total, nextCursor)
+	for _, t := range tasks {
+		fmt.Fprintf(h, "|%d:%d", t.ID, t.Version)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:20] + `"`
+}
+
+// parseETags separa la lista de entity-tags de un header If-Match / If-None-Match
+func parseETags(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// hasIfMatch indica si la solicitud incluye una precondición If-Match
+func hasIfMatch(r *http.Request) bool {
+	return r.Header.Get("If-Match") != ""
+}
+
+// ifMatch evalúa If-Match con comparación fuerte (RFC 7232 §3.1)
+// Retorna true si el header está ausente, es "*" o contiene el ETag actual
+func ifMatch(r *http.Request, current string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, tag := range parseETags(header) {
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// ifNoneMatch evalúa If-None-Match con comparación débil (RFC 7232 §3.2)
+// Retorna true si el cliente ya tiene la representación actual y se puede responder 304
+func ifNoneMatch(r *http.Request, current string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	current = strings.TrimPrefix(current, "W/")
+	for _, tag := range parseETags(header) {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNotModified responde 304 incluyendo el ETag vigente
+func writeNotModified(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// writePreconditionFailed responde 412 cuando el ETag del cliente no corresponde a la versión actual
+func writePreconditionFailed(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, preconditionProblem(r))
+}
+
+// preconditionProblem Problem 412 de una escritura condicionada a una versión que ya cambió
+func preconditionProblem(r *http.Request) *Problem {
+	return newProblem(r, http.StatusPreconditionFailed, CodePreconditionFailed,
+		"The task was modified since it was retrieved; fetch it again and retry")
+}
+
+// checkIfMatch carga la versión actual de la tarea y evalúa If-Match.
+// Retorna la versión esperada para la escritura condicionada (0 si no hay precondición)
+// y ok=false si ya se respondió con un error (404 o 412).
+func (h *taskHandler) checkIfMatch(w http.ResponseWriter, r *http.Request, id uint) (uint, bool) {
+	return checkIfMatchWith(w, r, id, h.repo.GetTaskByID)
+}
+
+// checkIfMatchWith evalúa If-Match contra la tarea obtenida con load (ej. también desde la papelera)
+func checkIfMatchWith(w http.ResponseWriter, r *http.Request, id uint, load func(context.Context, uint) (*models.Task, error)) (uint, bool) {
+	if !hasIfMatch(r) {
+		return 0, true
+	}
+	current, err := load(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return 0, false
+	}
+	if !ifMatch(r, taskETag(current)) {
+		writePreconditionFailed(w, r)
+		return 0, false
+	}
+	return current.Version, true
+}
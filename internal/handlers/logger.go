@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// redactedParams parámetros de consulta que contienen credenciales y no deben quedar en los logs
+// (el token de los feeds iCalendar viaja en la URL porque los clientes de calendario no envían headers)
+var redactedParams = []string{calendarTokenParam}
+
+// Logger middleware equivalente a middleware.Logger de chi que registra la URL sin el valor de redactedParams
+var Logger = middleware.RequestLogger(RedactingLogFormatter(&middleware.DefaultLogFormatter{
+	Logger:  log.New(os.Stdout, "", log.LstdFlags),
+	NoColor: runtime.GOOS == "windows",
+}))
+
+// RedactingLogFormatter envuelve un LogFormatter para que registre la solicitud con los parámetros
+// de redactedParams reemplazados por "REDACTED"; el handler sigue recibiendo la URL original
+func RedactingLogFormatter(f middleware.LogFormatter) middleware.LogFormatter {
+	return &redactingLogFormatter{LogFormatter: f}
+}
+
+// redactingLogFormatter implementación de RedactingLogFormatter
+type redactingLogFormatter struct {
+	middleware.LogFormatter
+}
+
+// NewLogEntry implementa middleware.LogFormatter
+func (f *redactingLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	query := r.URL.Query()
+	redacted := false
+	for _, param := range redactedParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return f.LogFormatter.NewLogEntry(r)
+	}
+	u := *r.URL
+	u.RawQuery = query.Encode()
+	logged := *r
+	logged.URL = &u
+	logged.RequestURI = u.RequestURI()
+	return f.LogFormatter.NewLogEntry(&logged)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// problemContentType tipo de contenido de las respuestas de error (RFC 7807)
+const problemContentType = "application/problem+json"
+
+// Códigos de error estables que los clientes pueden usar para distinguir fallos
+const (
+	CodeInvalidID         = "invalid_id"         // El ID de la URL no es un entero válido
+	CodeInvalidPayload    = "invalid_payload"    // El cuerpo no es JSON válido
+	CodeInvalidQuery      = "invalid_query"      // Parámetros de consulta inválidos
+	CodeValidationFailed  = "validation_failed"  // El recurso no cumple las reglas de negocio
+	CodeTaskNotFound      = "task_not_found"     // La tarea no existe
+	CodeDuplicateName     = "duplicate_name"     // Ya existe una tarea con ese nombre en el proyecto
+	CodeTagNotFound       = "tag_not_found"      // La etiqueta no existe
+	CodeProjectNotFound   = "project_not_found"  // El proyecto no existe
+	CodeDuplicateProject  = "duplicate_project"  // Ya existe un proyecto con ese nombre
+	CodeProjectNotEmpty   = "project_not_empty"  // El proyecto aún tiene tareas
+	CodeHierarchyCycle    = "hierarchy_cycle"    // parent_id generaría un ciclo
+	CodeOpenSubtasks      = "open_subtasks"      // La tarea tiene subtareas sin completar
+	CodeDependencyCycle   = "dependency_cycle"   // La dependencia generaría un ciclo
+	CodeTaskBlocked       = "task_blocked"       // La tarea tiene bloqueadores sin completar
+	CodeInvalidTransition = "invalid_transition" // El flujo de trabajo no permite el cambio de estado
+	CodeStatusInUse       = "status_in_use"      // El nuevo flujo no incluye estados usados por tareas
+	CodeConflict          = "conflict"           // Otra solicitud modificó el recurso al mismo tiempo
+	CodeDuplicateTag      = "duplicate_tag"      // Ya existe una etiqueta con ese nombre
+	CodeNotFound          = "not_found"          // La ruta no existe
+	CodeMethodNotAllowed  = "method_not_allowed"
+	CodeQueryTimeout      = "query_timeout"       // La consulta superó el tiempo límite de la solicitud
+	CodeUnavailable       = "service_unavailable" // La base de datos no está disponible o la solicitud se canceló
+	CodeInternal          = "internal_error"      // Error inesperado del servidor
+)
+
+// Problem representa un error HTTP con el formato application/problem+json (RFC 7807)
+// Los campos code, request_id, errors y operation son extensiones propias de la API
+type Problem struct {
+	Type      string              `json:"type"`                 // URI que identifica el tipo de problema
+	Title     string              `json:"title"`                // Resumen corto (texto del código HTTP)
+	Status    int                 `json:"status"`               // Código HTTP
+	Detail    string              `json:"detail,omitempty"`     // Explicación específica de esta ocurrencia
+	Instance  string              `json:"instance,omitempty"`   // Ruta de la solicitud que falló
+	Code      string              `json:"code"`                 // Código de error estable (ver constantes Code*)
+	RequestID string              `json:"request_id,omitempty"` // ID de la solicitud para correlacionar con los logs
+	Errors    []models.FieldError `json:"errors,omitempty"`     // Errores de validación por campo
+	Operation *int                `json:"operation,omitempty"`  // Posición de la operación que falló en POST /tasks/bulk
+}
+
+// newProblem crea un Problem con los campos comunes ya completados a partir de la solicitud
+func newProblem(r *http.Request, status int, code, detail string) *Problem {
+	return &Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+// writeProblem serializa el Problem como application/problem+json
+func writeProblem(w http.ResponseWriter, p *Problem) {
+	if p.RequestID != "" {
+		w.Header().Set(middleware.RequestIDHeader, p.RequestID)
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("Error encoding problem response: %v", err)
+	}
+}
+
+// writeError responde con un Problem construido a partir del código y el detalle
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	writeProblem(w, newProblem(r, status, code, detail))
+}
+
+// writeBodyError responde 413 si el cuerpo supera el tamaño permitido (ver http.MaxBytesReader),
+// o 400 con el detalle indicado si no se pudo leer o interpretar
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error, detail string) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		writeError(w, r, http.StatusRequestEntityTooLarge, CodePayloadTooLarge,
+			fmt.Sprintf("The request body must be at most %d bytes", maxErr.Limit))
+		return
+	}
+	writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, detail)
+}
+
+// writeValidationError responde 422 con el detalle de cada campo inválido
+func writeValidationError(w http.ResponseWriter, r *http.Request, errs models.ValidationErrors) {
+	writeProblem(w, validationProblem(r, errs))
+}
+
+// validationProblem Problem 422 con el detalle de cada campo inválido
+func validationProblem(r *http.Request, errs models.ValidationErrors) *Problem {
+	p := newProblem(r, http.StatusUnprocessableEntity, CodeValidationFailed, "One or more fields are invalid")
+	p.Errors = errs
+	return p
+}
+
+// writeDomainError responde con el Problem correspondiente al error (ver domainProblem)
+func writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	p := domainProblem(r, err)
+	if p.Status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", retryAfterSeconds)
+	}
+	writeProblem(w, p)
+}
+
+// retryAfterSeconds segundos que el cliente debe esperar antes de reintentar tras un 503
+const retryAfterSeconds = "5"
+
+// domainProblem traduce un error de los modelos o del repositorio al Problem correspondiente:
+//   - Tarea, etiqueta o proyecto inexistente -> 404
+//   - Violación del índice único de nombre (tarea, etiqueta o proyecto) -> 409
+//   - Proyecto con tareas al eliminarlo, ciclo en la jerarquía o subtareas abiertas -> 409
+//   - Ciclo en las dependencias o tarea bloqueada al iniciarla -> 409
+//   - Transición no permitida por el flujo de trabajo, o flujo que omite estados en uso -> 409
+//   - Flujo de trabajo modificado concurrentemente -> 409
+//   - Versión modificada concurrentemente -> 412
+//   - Errores de validación -> 422
+//   - Consulta inválida -> 400
+//   - Tiempo límite de la solicitud agotado (ver QueryTimeout) -> 504
+//   - Solicitud cancelada o conexión con la base de datos perdida -> 503
+//   - Cualquier otro -> 500 (el detalle solo se registra en el log)
+func domainProblem(r *http.Request, err error) *Problem {
+	var validationErrs models.ValidationErrors
+	switch {
+	case errors.Is(err, repository.ErrTaskNotFound):
+		return newProblem(r, http.StatusNotFound, CodeTaskNotFound, "Task not found")
+	case errors.Is(err, repository.ErrDuplicateName):
+		return newProblem(r, http.StatusConflict, CodeDuplicateName, "A task with this name already exists in this project")
+	case errors.Is(err, repository.ErrTagNotFound):
+		return newProblem(r, http.StatusNotFound, CodeTagNotFound, "Tag not found")
+	case errors.Is(err, repository.ErrDuplicateTag):
+		return newProblem(r, http.StatusConflict, CodeDuplicateTag, "A tag with this name already exists")
+	case errors.Is(err, repository.ErrProjectNotFound):
+		return newProblem(r, http.StatusNotFound, CodeProjectNotFound, "Project not found")
+	case errors.Is(err, repository.ErrDuplicateProject):
+		return newProblem(r, http.StatusConflict, CodeDuplicateProject, "A project with this name already exists")
+	case errors.Is(err, repository.ErrHierarchyCycle):
+		return newProblem(r, http.StatusConflict, CodeHierarchyCycle, "A task cannot be nested under itself or one of its subtasks")
+	case errors.Is(err, repository.ErrOpenSubtasks):
+		return newProblem(r, http.StatusConflict, CodeOpenSubtasks, "The task cannot be completed while it has open subtasks")
+	case errors.Is(err, repository.ErrDependencyCycle):
+		return newProblem(r, http.StatusConflict, CodeDependencyCycle, "A task cannot depend on itself or on a task it blocks")
+	case errors.Is(err, repository.ErrTaskBlocked):
+		return newProblem(r, http.StatusConflict, CodeTaskBlocked, "The task cannot be started while tasks blocking it are not completed")
+	case errors.Is(err, repository.ErrInvalidTransition):
+		return newProblem(r, http.StatusConflict, CodeInvalidTransition, "The workflow does not allow this status change")
+	case errors.Is(err, repository.ErrStatusInUse):
+		return newProblem(r, http.StatusConflict, CodeStatusInUse, err.Error())
+	case errors.Is(err, repository.ErrConflict):
+		return newProblem(r, http.StatusConflict, CodeConflict, "The resource was modified by another request; try again")
+	case errors.Is(err, repository.ErrProjectNotEmpty):
+		return newProblem(r, http.StatusConflict, CodeProjectNotEmpty, "The project still has tasks; move or delete them first, or archive the project")
+	case errors.Is(err, repository.ErrVersionConflict):
+		return preconditionProblem(r)
+	case errors.As(err, &validationErrs):
+		return validationProblem(r, validationErrs)
+	case errors.Is(err, repository.ErrInvalidQuery):
+		return newProblem(r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(r.Context().Err(), context.DeadlineExceeded):
+		// El driver no siempre envuelve el error del contexto; el plazo vencido de la solicitud basta
+		log.Printf("Query timeout [%s]: %v", middleware.GetReqID(r.Context()), err)
+		return newProblem(r, http.StatusGatewayTimeout, CodeQueryTimeout, "The request took too long to complete; try again or narrow it down")
+	case errors.Is(err, context.Canceled), errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		log.Printf("Database unavailable [%s]: %v", middleware.GetReqID(r.Context()), err)
+		return newProblem(r, http.StatusServiceUnavailable, CodeUnavailable, "The service is temporarily unavailable; try again later")
+	default:
+		log.Printf("Unexpected error [%s]: %v", middleware.GetReqID(r.Context()), err)
+		return newProblem(r, http.StatusInternalServerError, CodeInternal, "An unexpected error occurred")
+	}
+}
+
+// writeJSON responde con el valor serializado como application/json y el código indicado
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// NotFoundHandler responde con un Problem 404 para rutas inexistentes
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, CodeNotFound, "The requested resource does not exist")
+}
+
+// MethodNotAllowedHandler responde con un Problem 405 cuando la ruta no admite el método
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method "+r.Method+" is not allowed on this resource")
+}
+
+// Recoverer middleware que captura pánicos, registra el stack trace
+// y responde con un Problem 500 en lugar de cerrar la conexión
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				log.Printf("Panic [%s]: %v\n%s", middleware.GetReqID(r.Context()), rec, debug.Stack())
+				writeError(w, r, http.StatusInternalServerError, CodeInternal, "An unexpected error occurred")
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// ProjectHandler define los handlers HTTP de proyectos
+// Las tareas de un proyecto se gestionan con TaskHandler (GetProjectTasksHandler, CreateProjectTaskHandler)
+type ProjectHandler interface {
+	GetProjectsHandler(w http.ResponseWriter, r *http.Request)
+	CreateProjectHandler(w http.ResponseWriter, r *http.Request)
+	GetProjectHandler(w http.ResponseWriter, r *http.Request)
+	UpdateProjectHandler(w http.ResponseWriter, r *http.Request)
+	DeleteProjectHandler(w http.ResponseWriter, r *http.Request)
+	ArchiveProjectHandler(w http.ResponseWriter, r *http.Request)
+	UnarchiveProjectHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// projectHandler implementación de ProjectHandler
+type projectHandler struct {
+	repo repository.ProjectRepository
+}
+
+// NewProjectHandler crea un nuevo ProjectHandler con el repositorio proporcionado.
+func NewProjectHandler(repo repository.ProjectRepository) ProjectHandler {
+	return &projectHandler{repo: repo}
+}
+
+// projectListResponse cuerpo de respuesta de GET /projects
+type projectListResponse struct {
+	Items []models.Project `json:"items"`
+}
+
+// projectRequest cuerpo de POST /projects y PUT /projects/{pid}
+type projectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetProjectsHandler lista los proyectos ordenados por nombre.
+// Método HTTP: GET
+// Ruta: /projects?include_archived=
+func (h *projectHandler) GetProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	includeArchived, err := parseBoolParam(r.URL.Query().Get("include_archived"), "include_archived")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+		return
+	}
+	projects, err := h.repo.GetProjects(r.Context(), includeArchived)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, projectListResponse{Items: projects})
+}
+
+// CreateProjectHandler crea un proyecto.
+// Método HTTP: POST
+// Ruta: /projects
+func (h *projectHandler) CreateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req projectRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	project := models.Project{Name: req.Name, Description: req.Description}
+	if err := project.Validate(); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	if err := h.repo.CreateProject(r.Context(), &project); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, project)
+}
+
+// GetProjectHandler obtiene un proyecto por ID.
+// Método HTTP: GET
+// Ruta: /projects/{pid}
+func (h *projectHandler) GetProjectHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.repo.GetProjectByID(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+// UpdateProjectHandler actualiza nombre y descripción de un proyecto.
+// Método HTTP: PUT
+// Ruta: /projects/{pid}
+func (h *projectHandler) UpdateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	var req projectRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	project := models.Project{Name: req.Name, Description: req.Description}
+	if err := project.Validate(); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	project.ID = id
+	if err := h.repo.UpdateProject(r.Context(), &project); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+// DeleteProjectHandler elimina un proyecto sin tareas (409 si aún tiene tareas).
+// Método HTTP: DELETE
+// Ruta: /projects/{pid}
+func (h *projectHandler) DeleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.DeleteProject(r.Context(), id); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ArchiveProjectHandler archiva un proyecto; sus tareas dejan de aparecer en los listados por defecto.
+// Método HTTP: POST
+// Ruta: /projects/{pid}/archive
+func (h *projectHandler) ArchiveProjectHandler(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+// UnarchiveProjectHandler reactiva un proyecto archivado.
+// Método HTTP: POST
+// Ruta: /projects/{pid}/unarchive
+func (h *projectHandler) UnarchiveProjectHandler(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+// setArchived cambia el estado de archivado y responde con el proyecto actualizado
+func (h *projectHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.repo.SetArchived(r.Context(), id, archived)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+// parseProjectID extrae el parámetro {pid} de la URL.
+// Si no es un entero positivo responde 400 y retorna ok=false.
+func parseProjectID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "pid"), 10, 0)
+	if err != nil || id == 0 {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidID, "Invalid project ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// taskListResponse cuerpo de respuesta de GET /tasks
+type taskListResponse struct {
+	Items      []models.Task `json:"items"`                 // Tareas de la página actual
+	Total      int64         `json:"total"`                 // Total de tareas que cumplen los filtros
+	Limit      int           `json:"limit"`                 // Límite aplicado
+	Offset     int           `json:"offset"`                // Offset aplicado
+	NextCursor string        `json:"next_cursor,omitempty"` // Cursor de la siguiente página, si existe
+}
+
+// newTaskListResponse construye la respuesta de listado a partir de una página del repositorio
+func newTaskListResponse(page *repository.TaskPage) taskListResponse {
+	items := page.Tasks
+	if items == nil {
+		items = []models.Task{} // Serializa como [] en lugar de null
+	}
+	return taskListResponse{
+		Items:      items,
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// searchResult elemento de la respuesta de GET /tasks/search
+type searchResult struct {
+	Task       models.Task      `json:"task"`       // Tarea encontrada
+	Rank       float64          `json:"rank"`       // Relevancia (mayor es más relevante)
+	Highlights searchHighlights `json:"highlights"` // Coincidencias resaltadas con <mark></mark>
+}
+
+// searchHighlights fragmentos resaltados de cada campo indexado
+type searchHighlights struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// searchResponse cuerpo de respuesta de GET /tasks/search
+type searchResponse struct {
+	Items  []searchResult `json:"items"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// newSearchResponse construye la respuesta de búsqueda a partir de una página del repositorio
+func newSearchResponse(page *repository.SearchPage) searchResponse {
+	resp := searchResponse{Items: []searchResult{}, Total: page.Total, Limit: page.Limit, Offset: page.Offset}
+	for _, res := range page.Results {
+		resp.Items = append(resp.Items, searchResult{
+			Task: res.Task,
+			Rank: res.Rank,
+			Highlights: searchHighlights{
+				Name:        res.NameHighlight,
+				Description: res.DescriptionHighlight,
+			},
+		})
+	}
+	return resp
+}
+
+// parseSearchQuery convierte los parámetros q, limit y offset en opciones de búsqueda
+func parseSearchQuery(r *http.Request) (repository.SearchOptions, error) {
+	q := r.URL.Query()
+	opts := repository.SearchOptions{Query: q.Get("q")}
+	if opts.Query == "" {
+		return opts, fmt.Errorf("query parameter q is required")
+	}
+
+	var err error
+	if opts.Limit, err = parseIntParam(q.Get("limit"), "limit"); err != nil {
+		return opts, err
+	}
+	if opts.Offset, err = parseIntParam(q.Get("offset"), "offset"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// parseTaskQuery convierte los parámetros de la URL en opciones de consulta del repositorio
+// Parámetros soportados: status (repetible), category (repetible), priority (repetible), tag (repetible), tag_match, created_after, created_before, due_after, due_before,
+// overdue, include_archived, sort, limit, offset, cursor
+// Retorna: error descriptivo si algún parámetro tiene un formato inválido
+func parseTaskQuery(r *http.Request) (repository.TaskQueryOptions, error) {
+	q := r.URL.Query()
+	var opts repository.TaskQueryOptions
+
+	for _, raw := range q["status"] {
+		status := models.Status(raw)
+		if err := status.IsValid(); err != nil {
+			return opts, err
+		}
+		opts.Status = append(opts.Status, status)
+	}
+
+	for _, raw := range q["category"] {
+		category := models.StatusCategory(raw)
+		if err := category.IsValid(); err != nil {
+			return opts, err
+		}
+		opts.Category = append(opts.Category, category)
+	}
+
+	for _, raw := range q["priority"] {
+		priority := models.Priority(raw)
+		if err := priority.IsValid(); err != nil {
+			return opts, err
+		}
+		opts.Priority = append(opts.Priority, priority)
+	}
+
+	opts.Tags = q["tag"]
+	opts.TagMatch = repository.TagMatch(q.Get("tag_match"))
+
+	var err error
+	if opts.CreatedAfter, err = parseTimeParam(q.Get("created_after"), "created_after"); err != nil {
+		return opts, err
+	}
+	if opts.CreatedBefore, err = parseTimeParam(q.Get("created_before"), "created_before"); err != nil {
+		return opts, err
+	}
+	if opts.DueAfter, err = parseTimeParam(q.Get("due_after"), "due_after"); err != nil {
+		return opts, err
+	}
+	if opts.DueBefore, err = parseTimeParam(q.Get("due_before"), "due_before"); err != nil {
+		return opts, err
+	}
+	if opts.Overdue, err = parseBoolParam(q.Get("overdue"), "overdue"); err != nil {
+		return opts, err
+	}
+	if opts.IncludeArchived, err = parseBoolParam(q.Get("include_archived"), "include_archived"); err != nil {
+		return opts, err
+	}
+
+	if raw := q.Get("sort"); raw != "" {
+		if opts.Sort, err = repository.ParseSort(raw); err != nil {
+			return opts, err
+		}
+	}
+
+	if opts.Limit, err = parseIntParam(q.Get("limit"), "limit"); err != nil {
+		return opts, err
+	}
+	if opts.Offset, err = parseIntParam(q.Get("offset"), "offset"); err != nil {
+		return opts, err
+	}
+	opts.Cursor = q.Get("cursor")
+
+	return opts, nil
+}
+
+// parseTimeParam acepta fechas RFC 3339 o con formato YYYY-MM-DD; retorna nil si el valor está vacío
+func parseTimeParam(raw, name string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s: expected RFC 3339 timestamp or YYYY-MM-DD date", name)
+}
+
+// parseBoolParam convierte un parámetro booleano (true/false/1/0); retorna false si el valor está vacío
+func parseBoolParam(raw, name string) (bool, error) {
+	if raw == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: expected true or false", name)
+	}
+	return b, nil
+}
+
+// parseIntParam convierte un parámetro entero no negativo; retorna 0 si el valor está vacío
+func parseIntParam(raw, name string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: expected a non-negative integer", name)
+	}
+	return n, nil
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// TagHandler define los handlers HTTP de etiquetas y de su asignación a tareas
+type TagHandler interface {
+	GetTagsHandler(w http.ResponseWriter, r *http.Request)
+	CreateTagHandler(w http.ResponseWriter, r *http.Request)
+	GetTagHandler(w http.ResponseWriter, r *http.Request)
+	RenameTagHandler(w http.ResponseWriter, r *http.Request)
+	DeleteTagHandler(w http.ResponseWriter, r *http.Request)
+	MergeTagsHandler(w http.ResponseWriter, r *http.Request)
+	AddTaskTagHandler(w http.ResponseWriter, r *http.Request)
+	RemoveTaskTagHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// tagHandler implementación de TagHandler
+type tagHandler struct {
+	tags  repository.TagRepository
+	tasks repository.TaskRepository // Para devolver la tarea actualizada al asignar o quitar etiquetas
+}
+
+// NewTagHandler crea un nuevo TagHandler con los repositorios proporcionados.
+func NewTagHandler(tags repository.TagRepository, tasks repository.TaskRepository) TagHandler {
+	return &tagHandler{tags: tags, tasks: tasks}
+}
+
+// tagListResponse cuerpo de respuesta de GET /tags
+type tagListResponse struct {
+	Items []models.Tag `json:"items"`
+}
+
+// tagNameRequest cuerpo de POST /tags y PUT /tags/{tag}
+type tagNameRequest struct {
+	Name string `json:"name"`
+}
+
+// mergeTagsRequest cuerpo de POST /tags/merge
+type mergeTagsRequest struct {
+	Sources []string `json:"sources"` // Etiquetas que se eliminan
+	Target  string   `json:"target"`  // Etiqueta que las reemplaza (se crea si no existe)
+}
+
+// decodeJSON decodifica el cuerpo de la solicitud; responde 400 y retorna false si no es JSON válido
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, "Invalid request payload: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// GetTagsHandler lista todas las etiquetas ordenadas por nombre.
+// Método HTTP: GET
+// Ruta: /tags
+func (h *tagHandler) GetTagsHandler(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tags.GetTags(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tagListResponse{Items: tags})
+}
+
+// CreateTagHandler crea una etiqueta.
+// Método HTTP: POST
+// Ruta: /tags
+func (h *tagHandler) CreateTagHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req tagNameRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	tag := models.Tag{Name: req.Name}
+	if err := h.tags.CreateTag(r.Context(), &tag); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, tag)
+}
+
+// GetTagHandler obtiene una etiqueta por nombre.
+// Método HTTP: GET
+// Ruta: /tags/{tag}
+func (h *tagHandler) GetTagHandler(w http.ResponseWriter, r *http.Request) {
+	tag, err := h.tags.GetTagByName(r.Context(), chi.URLParam(r, "tag"))
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// RenameTagHandler renombra una etiqueta; todas las tareas etiquetadas cambian a la vez.
+// Método HTTP: PUT
+// Ruta: /tags/{tag}
+func (h *tagHandler) RenameTagHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req tagNameRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	tag, err := h.tags.RenameTag(r.Context(), chi.URLParam(r, "tag"), req.Name)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTagHandler elimina una etiqueta y la quita de todas las tareas.
+// Método HTTP: DELETE
+// Ruta: /tags/{tag}
+func (h *tagHandler) DeleteTagHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.tags.DeleteTag(r.Context(), chi.URLParam(r, "tag")); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergeTagsHandler une varias etiquetas en una sola de forma atómica.
+// Método HTTP: POST
+// Ruta: /tags/merge
+func (h *tagHandler) MergeTagsHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req mergeTagsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.Sources) == 0 {
+		writeValidationError(w, r, models.ValidationErrors{{Field: "sources", Message: "at least one source tag is required"}})
+		return
+	}
+	tag, err := h.tags.MergeTags(r.Context(), req.Sources, req.Target)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// AddTaskTagHandler asigna una etiqueta a una tarea (la crea si no existe).
+// Método HTTP: POST
+// Ruta: /tasks/{id}/tags/{tag}
+func (h *tagHandler) AddTaskTagHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTaskTag(w, r, h.tags.AddTaskTag)
+}
+
+// RemoveTaskTagHandler quita una etiqueta de una tarea.
+// Método HTTP: DELETE
+// Ruta: /tasks/{id}/tags/{tag}
+func (h *tagHandler) RemoveTaskTagHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTaskTag(w, r, h.tags.RemoveTaskTag)
+}
+
+// changeTaskTag aplica el cambio de etiqueta y responde con la tarea actualizada y su nuevo ETag
+func (h *tagHandler) changeTaskTag(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, taskID uint, name string) error) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	if err := change(r.Context(), id, chi.URLParam(r, "tag")); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	task, err := h.tasks.GetTaskByID(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", taskETag(task))
+	writeJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// Límites de POST /tasks/bulk
+const (
+	maxBulkOperations = 1000    // Operaciones por solicitud
+	maxBulkBodySize   = 8 << 20 // Tamaño máximo del cuerpo
+)
+
+// Modos de ejecución de un lote
+const (
+	bulkModeAtomic  = "atomic"  // Todo o nada: el primer error revierte el lote
+	bulkModePartial = "partial" // Cada operación se confirma o falla por separado
+)
+
+// bulkRequest cuerpo de POST /tasks/bulk
+type bulkRequest struct {
+	Mode       string                 `json:"mode"` // atomic (por defecto) o partial
+	Operations []bulkOperationRequest `json:"operations"`
+}
+
+// bulkOperationRequest operación del lote
+//   - create: task con la tarea a crear (mismos campos que POST /tasks)
+//   - update: id, version opcional y task con los campos a modificar (JSON Merge Patch, como PATCH /tasks/{id})
+//   - upsert: task con los campos de la tarea; si el proyecto ya tiene una tarea con ese nombre se le
+//     aplican como JSON Merge Patch, si no, se crea
+//   - delete: id y version opcional
+type bulkOperationRequest struct {
+	Action  repository.BulkAction `json:"action"`
+	ID      uint                  `json:"id"`
+	Version uint                  `json:"version"` // Versión esperada (0 u omitida para no comprobarla)
+	Task    json.RawMessage       `json:"task"`
+}
+
+// bulkResponse cuerpo de respuesta de POST /tasks/bulk
+type bulkResponse struct {
+	Mode      string           `json:"mode"`
+	Succeeded int              `json:"succeeded"` // Operaciones aplicadas
+	Failed    int              `json:"failed"`    // Operaciones fallidas (solo en modo partial)
+	Results   []bulkItemResult `json:"results"`
+}
+
+// bulkItemResult resultado de una operación, en el mismo orden que la solicitud
+type bulkItemResult struct {
+	Index  int                   `json:"index"`
+	Action repository.BulkAction `json:"action"` // Acción aplicada (en upsert, create o update)
+	Status int                   `json:"status"` // Código HTTP equivalente a la operación individual
+	ID     uint                  `json:"id,omitempty"`
+	Task   *models.Task          `json:"task,omitempty"`  // Tarea creada o actualizada
+	Error  *Problem              `json:"error,omitempty"` // Error de la operación
+}
+
+// BulkTasksHandler maneja la ejecución de un lote de creaciones, actualizaciones y eliminaciones
+// en una sola transacción.
+// En modo atomic (por defecto) el primer error revierte el lote y se responde con ese error, indicando
+// la operación en el campo operation; en modo partial cada operación se confirma o falla por separado
+// y el resultado de cada una se informa en results.
+// Método HTTP: POST
+// Ruta: /tasks/bulk
+func (h *taskHandler) BulkTasksHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req bulkRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, r, err, "Invalid request payload: "+err.Error())
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = bulkModeAtomic
+	}
+	if req.Mode != bulkModeAtomic && req.Mode != bulkModePartial {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidPayload,
+			fmt.Sprintf("Invalid mode %q: must be %s or %s", req.Mode, bulkModeAtomic, bulkModePartial))
+		return
+	}
+	if len(req.Operations) == 0 || len(req.Operations) > maxBulkOperations {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidPayload,
+			fmt.Sprintf("A batch must contain between 1 and %d operations", maxBulkOperations))
+		return
+	}
+
+	ops := make([]repository.BulkOperation, len(req.Operations))
+	for i, opReq := range req.Operations {
+		op, err := opReq.toOperation()
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, fmt.Sprintf("Invalid operation %d: %v", i, err))
+			return
+		}
+		ops[i] = op
+	}
+
+	opts := repository.BulkOptions{Atomic: req.Mode == bulkModeAtomic}
+	results, err := h.repo.WithCaller(requestCaller(r)).BulkTasks(r.Context(), ops, opts)
+	var bulkErr *repository.BulkError
+	if errors.As(err, &bulkErr) {
+		p := domainProblem(r, bulkErr.Err)
+		p.Operation = &bulkErr.Index
+		writeProblem(w, p)
+		return
+	}
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	resp := bulkResponse{Mode: req.Mode, Results: make([]bulkItemResult, len(results))}
+	for i, res := range results {
+		item := bulkItemResult{Index: i, Action: res.Action, ID: ops[i].ID, Task: res.Task}
+		switch {
+		case res.Err != nil:
+			item.Error = domainProblem(r, res.Err)
+			item.Status = item.Error.Status
+			resp.Failed++
+		case res.Action == repository.BulkCreate:
+			item.Status = http.StatusCreated
+			item.ID = res.Task.ID
+			resp.Succeeded++
+		case res.Action == repository.BulkDelete:
+			item.Status = http.StatusNoContent
+			resp.Succeeded++
+		default:
+			item.Status = http.StatusOK
+			item.ID = res.Task.ID
+			resp.Succeeded++
+		}
+		resp.Results[i] = item
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// toOperation convierte la operación recibida en una operación del repositorio
+// Retorna error si el cuerpo de la tarea no es un objeto JSON válido o falta el ID
+// (una acción desconocida se informa como error de validación de la operación)
+func (req *bulkOperationRequest) toOperation() (repository.BulkOperation, error) {
+	op := repository.BulkOperation{Action: req.Action, ID: req.ID, Version: req.Version}
+	switch req.Action {
+	case repository.BulkCreate, repository.BulkUpsert:
+		if !isJSONObject(req.Task) {
+			return op, errors.New("task must be a JSON object")
+		}
+		var task models.Task
+		if err := json.Unmarshal(req.Task, &task); err != nil {
+			return op, err
+		}
+		task.ID = 0 // El ID lo asigna la base de datos
+		defaultPriority(&task)
+		op.Task = &task
+		if req.Action == repository.BulkUpsert {
+			patch := req.Task
+			op.Apply = func(existing *models.Task) ([]string, error) {
+				return patchTask(existing, mergePatchContentType, patch)
+			}
+		}
+
+	case repository.BulkUpdate:
+		if req.ID == 0 {
+			return op, errors.New("id is required")
+		}
+		if !isJSONObject(req.Task) {
+			return op, errors.New("task must be a JSON object")
+		}
+		patch := req.Task
+		op.Apply = func(task *models.Task) ([]string, error) {
+			return patchTask(task, mergePatchContentType, patch)
+		}
+
+	case repository.BulkDelete:
+		if req.ID == 0 {
+			return op, errors.New("id is required")
+		}
+	}
+	return op, nil
+}
+
+// isJSONObject indica si el valor es un objeto JSON válido
+func isJSONObject(raw json.RawMessage) bool {
+	return json.Valid(raw) && bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{"))
+}
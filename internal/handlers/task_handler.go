@@ -2,7 +2,6 @@ package handlers
 
 import (
     "encoding/json"
-    "log"
     "net/http"
     "strconv"
 
@@ -13,11 +12,23 @@ import (
 
 // TaskHandler define la interfaz para manejar operaciones CRUD relacionadas con tareas.
 type TaskHandler interface {
-    CreateTaskHandler(w http.ResponseWriter, r *http.Request)  // Maneja la creación de una nueva tarea.
-    GetTasksHandler(w http.ResponseWriter, r *http.Request)    // Maneja la obtención de todas las tareas.
-    GetTaskByIDHandler(w http.ResponseWriter, r *http.Request) // Maneja la obtención de una tarea por su ID.
-    UpdateTaskHandler(w http.ResponseWriter, r *http.Request)  // Maneja la actualización de una tarea existente.
-    DeleteTaskHandler(w http.ResponseWriter, r *http.Request)  // Maneja la eliminación de una tarea.
+    CreateTaskHandler(w http.ResponseWriter, r *http.Request)        // Maneja la creación de una nueva tarea.
+    GetTasksHandler(w http.ResponseWriter, r *http.Request)          // Maneja la obtención de todas las tareas.
+    SearchTasksHandler(w http.ResponseWriter, r *http.Request)       // Maneja la búsqueda de texto completo.
+    GetTaskByIDHandler(w http.ResponseWriter, r *http.Request)       // Maneja la obtención de una tarea por su ID.
+    UpdateTaskHandler(w http.ResponseWriter, r *http.Request)        // Maneja la actualización de una tarea existente.
+    PatchTaskHandler(w http.ResponseWriter, r *http.Request)         // Maneja la actualización parcial de una tarea.
+    DeleteTaskHandler(w http.ResponseWriter, r *http.Request)        // Maneja la eliminación de una tarea.
+    BulkTasksHandler(w http.ResponseWriter, r *http.Request)         // Maneja un lote de creaciones, actualizaciones y eliminaciones.
+    ExportTasksHandler(w http.ResponseWriter, r *http.Request)       // Maneja la exportación de tareas a CSV, JSON o NDJSON.
+    ImportTasksHandler(w http.ResponseWriter, r *http.Request)       // Maneja la importación de tareas desde un archivo.
+    GetProjectTasksHandler(w http.ResponseWriter, r *http.Request)   // Maneja la obtención de las tareas de un proyecto.
+    CreateProjectTaskHandler(w http.ResponseWriter, r *http.Request) // Maneja la creación de una tarea en un proyecto.
+    GetSubtasksHandler(w http.ResponseWriter, r *http.Request)       // Maneja la obtención de las subtareas directas.
+    GetTaskTreeHandler(w http.ResponseWriter, r *http.Request)       // Maneja la obtención de la jerarquía de subtareas.
+    GetTaskHistoryHandler(w http.ResponseWriter, r *http.Request)    // Maneja la obtención del historial de estados.
+    GetTrashHandler(w http.ResponseWriter, r *http.Request)          // Maneja la obtención de las tareas eliminadas.
+    RestoreTaskHandler(w http.ResponseWriter, r *http.Request)       // Maneja la recuperación de una tarea eliminada.
 }
 
 // taskHandler implementa la interfaz TaskHandler y contiene una referencia al repositorio de tareas.
@@ -34,60 +45,123 @@ func NewTaskHandler(repo repository.TaskRepository) TaskHandler {
 // Método HTTP: POST
 // Ruta: /tasks
 func (h *taskHandler) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
+    h.createTask(w, r, nil)
+}
+
+// CreateProjectTaskHandler maneja la creación de una tarea dentro de un proyecto.
+// Método HTTP: POST
+// Ruta: /projects/{pid}/tasks
+func (h *taskHandler) CreateProjectTaskHandler(w http.ResponseWriter, r *http.Request) {
+    projectID, ok := parseProjectID(w, r)
+    if !ok {
+        return
+    }
+    h.createTask(w, r, &projectID)
+}
+
+// createTask decodifica, valida y crea la tarea.
+// Si projectID no es nil, la tarea se crea en ese proyecto sin importar el project_id del cuerpo.
+func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request, projectID *uint) {
     defer r.Body.Close() // Cerrar el cuerpo de la solicitud al finalizar.
 
     var task models.Task
     // Decodificar el cuerpo de la solicitud en una estructura Task.
     if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-        http.Error(w, "Invalid request payload", http.StatusBadRequest)
+        writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, "Invalid request payload: "+err.Error())
         return
     }
-
-    // Validar que el nombre de la tarea no esté vacío.
-    if task.Name == "" {
-        http.Error(w, "Task name is required", http.StatusBadRequest)
-        return
+    if projectID != nil {
+        task.ProjectID = projectID
     }
 
-    // Validar que el estado de la tarea sea válido.
-    if err := task.Status.IsValid(); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
+    // Si no se indica estado, el repositorio asigna el estado inicial del flujo de trabajo del proyecto.
+    defaultPriority(&task)
+
+    // Validar nombre, descripción y estado de la tarea.
+    if err := task.Validate(); err != nil {
+        writeDomainError(w, r, err)
         return
     }
 
     // Crear la tarea en el repositorio.
-    if err := h.repo.CreateTask(&task); err != nil {
-        log.Printf("Error creating task: %v", err) // Registrar el error para depuración.
-        http.Error(w, "Error creating task", http.StatusInternalServerError)
+    if err := h.repo.WithCaller(requestCaller(r)).CreateTask(r.Context(), &task); err != nil {
+        writeDomainError(w, r, err)
         return
     }
 
     // Responder con un código de estado 201 Created y devolver la tarea creada.
-    w.WriteHeader(http.StatusCreated)
-    if err := json.NewEncoder(w).Encode(task); err != nil {
-        log.Printf("Error encoding response: %v", err)
-        http.Error(w, "Failed to encode JSON response", http.StatusInternalServerError)
-    }
+    w.Header().Set("ETag", taskETag(&task))
+    writeJSON(w, http.StatusCreated, task)
 }
 
-// GetTasksHandler maneja la obtención de todas las tareas.
+// GetTasksHandler maneja la obtención paginada de tareas.
 // Método HTTP: GET
-// Ruta: /tasks
+// Ruta: /tasks?status=&priority=&tag=&include_archived=&created_after=&created_before=&sort=&limit=&offset=&cursor=
+// Por defecto se omiten las tareas de proyectos archivados.
 func (h *taskHandler) GetTasksHandler(w http.ResponseWriter, r *http.Request) {
-    // Obtener todas las tareas del repositorio.
-    tasks, err := h.repo.GetTasks()
+    h.listTasks(w, r, nil)
+}
+
+// GetProjectTasksHandler maneja la obtención paginada de las tareas de un proyecto.
+// Método HTTP: GET
+// Ruta: /projects/{pid}/tasks (mismos parámetros que /tasks)
+func (h *taskHandler) GetProjectTasksHandler(w http.ResponseWriter, r *http.Request) {
+    projectID, ok := parseProjectID(w, r)
+    if !ok {
+        return
+    }
+    h.listTasks(w, r, &projectID)
+}
+
+// listTasks responde con una página de tareas, opcionalmente limitada a un proyecto.
+func (h *taskHandler) listTasks(w http.ResponseWriter, r *http.Request, projectID *uint) {
+    // Interpretar filtros, ordenamiento y paginación de la URL.
+    opts, err := parseTaskQuery(r)
+    if err != nil {
+        writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+        return
+    }
+    opts.ProjectID = projectID
+
+    // Obtener la página de tareas del repositorio.
+    page, err := h.repo.GetTasks(r.Context(), opts)
     if err != nil {
-        log.Printf("Error retrieving tasks: %v", err) // Registrar el error para depuración.
-        http.Error(w, "Error retrieving tasks", http.StatusInternalServerError)
+        writeDomainError(w, r, err)
         return
     }
 
-    // Responder con un código de estado 200 OK y devolver las tareas como JSON.
-    w.WriteHeader(http.StatusOK)
-    if err := json.NewEncoder(w).Encode(tasks); err != nil {
-        log.Printf("Error encoding response: %v", err)
-        http.Error(w, "Failed to encode JSON response", http.StatusInternalServerError)
+    // Responder 304 si el cliente ya tiene esta misma página (polling barato).
+    etag := listETag(page.Tasks, page.Total, page.NextCursor)
+    if ifNoneMatch(r, etag) {
+        writeNotModified(w, etag)
+        return
     }
+
+    // Responder con un código de estado 200 OK y devolver la página como JSON.
+    w.Header().Set("ETag", etag)
+    writeJSON(w, http.StatusOK, newTaskListResponse(page))
+}
+
+// SearchTasksHandler maneja la búsqueda de texto completo sobre nombre y descripción.
+// Método HTTP: GET
+// Ruta: /tasks/search?q=&limit=&offset=
+func (h *taskHandler) SearchTasksHandler(w http.ResponseWriter, r *http.Request) {
+    // Interpretar el texto de búsqueda y la paginación.
+    opts, err := parseSearchQuery(r)
+    if err != nil {
+        writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+        return
+    }
+
+    // Buscar las tareas en el repositorio.
+    page, err := h.repo.SearchTasks(r.Context(), opts)
+    if err != nil {
+        writeDomainError(w, r, err)
+        return
+    }
+
+    // Responder con un código de estado 200 OK y devolver los resultados como JSON.
+    writeJSON(w, http.StatusOK, newSearchResponse(page))
 }
 
 // GetTaskByIDHandler maneja la obtención de una tarea por su ID.
@@ -95,101 +169,132 @@ func (h *taskHandler) GetTasksHandler(w http.ResponseWriter, r *http.Request) {
 // Ruta: /tasks/{id}
 func (h *taskHandler) GetTaskByIDHandler(w http.ResponseWriter, r *http.Request) {
     // Extraer el ID de la URL.
-    idStr := chi.URLParam(r, "id")
-    id, err := strconv.Atoi(idStr)
-    if err != nil {
-        http.Error(w, "Invalid task ID", http.StatusBadRequest)
+    id, ok := parseTaskID(w, r)
+    if !ok {
         return
     }
 
     // Obtener la tarea por su ID desde el repositorio.
-    task, err := h.repo.GetTaskByID(uint(id))
+    task, err := h.repo.GetTaskByID(r.Context(), id)
     if err != nil {
-        log.Printf("Error retrieving task: %v", err) // Registrar el error para depuración.
-        http.Error(w, "Task not found", http.StatusNotFound)
+        writeDomainError(w, r, err)
         return
     }
 
-    // Responder con un código de estado 200 OK y devolver la tarea como JSON.
-    w.WriteHeader(http.StatusOK)
-    if err := json.NewEncoder(w).Encode(task); err != nil {
-        log.Printf("Error encoding response: %v", err)
-        http.Error(w, "Failed to encode JSON response", http.StatusInternalServerError)
+    // Responder 304 si el cliente ya tiene la versión actual.
+    etag := taskETag(task)
+    if ifNoneMatch(r, etag) {
+        writeNotModified(w, etag)
+        return
     }
+
+    // Responder con un código de estado 200 OK y devolver la tarea como JSON.
+    w.Header().Set("ETag", etag)
+    writeJSON(w, http.StatusOK, task)
 }
 
 // UpdateTaskHandler maneja la actualización de una tarea existente.
 // Método HTTP: PUT
 // Ruta: /tasks/{id}
+// Header opcional If-Match: la actualización solo se aplica si coincide con el ETag actual (412 si no).
 func (h *taskHandler) UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
     defer r.Body.Close() // Cerrar el cuerpo de la solicitud al finalizar.
 
     // Extraer el ID de la URL.
-    idStr := chi.URLParam(r, "id")
-    id, err := strconv.Atoi(idStr)
-    if err != nil {
-        http.Error(w, "Invalid task ID", http.StatusBadRequest)
+    id, ok := parseTaskID(w, r)
+    if !ok {
         return
     }
 
     var task models.Task
     // Decodificar el cuerpo de la solicitud en una estructura Task.
     if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-        http.Error(w, "Invalid request payload", http.StatusBadRequest)
+        writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, "Invalid request payload: "+err.Error())
         return
     }
 
-    // Validar que el nombre de la tarea no esté vacío.
-    if task.Name == "" {
-        http.Error(w, "Name is required", http.StatusBadRequest)
+    // PUT reemplaza la tarea completa: sin prioridad explícita se usa la prioridad por defecto.
+    defaultPriority(&task)
+
+    // Validar nombre, descripción y estado de la tarea.
+    if err := task.Validate(); err != nil {
+        writeDomainError(w, r, err)
         return
     }
 
-    // Validar que el estado de la tarea sea válido si se proporciona.
-    if task.Status != "" {
-        if err := task.Status.IsValid(); err != nil {
-            http.Error(w, err.Error(), http.StatusBadRequest)
-            return
-        }
+    // Evaluar la precondición If-Match contra la versión almacenada.
+    version, ok := h.checkIfMatch(w, r, id)
+    if !ok {
+        return
     }
 
-    // Asignar el ID extraído de la URL a la tarea.
-    task.ID = uint(id)
+    // Asignar el ID extraído de la URL y la versión esperada a la tarea.
+    task.ID = id
+    task.Version = version
 
     // Actualizar la tarea en el repositorio.
-    if err := h.repo.UpdateTask(&task); err != nil {
-        log.Printf("Error updating task: %v", err) // Registrar el error para depuración.
-        http.Error(w, "Error updating task", http.StatusInternalServerError)
+    if err := h.repo.WithCaller(requestCaller(r)).UpdateTask(r.Context(), &task); err != nil {
+        writeDomainError(w, r, err)
         return
     }
 
     // Responder con un código de estado 200 OK y devolver la tarea actualizada como JSON.
-    w.WriteHeader(http.StatusOK)
-    if err := json.NewEncoder(w).Encode(task); err != nil {
-        log.Printf("Error encoding response: %v", err)
-        http.Error(w, "Failed to encode JSON response", http.StatusInternalServerError)
-    }
+    w.Header().Set("ETag", taskETag(&task))
+    writeJSON(w, http.StatusOK, task)
 }
 
 // DeleteTaskHandler maneja la eliminación de una tarea.
+// La tarea pasa a la papelera; con hard=true se elimina definitivamente (aunque ya esté en la papelera).
 // Método HTTP: DELETE
-// Ruta: /tasks/{id}
+// Ruta: /tasks/{id}?hard=
+// Header opcional If-Match: la eliminación solo se aplica si coincide con el ETag actual (412 si no).
 func (h *taskHandler) DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
     // Extraer el ID de la URL.
-    idStr := chi.URLParam(r, "id")
-    id, err := strconv.Atoi(idStr)
+    id, ok := parseTaskID(w, r)
+    if !ok {
+        return
+    }
+
+    hard, err := parseBoolParam(r.URL.Query().Get("hard"), "hard")
     if err != nil {
-        http.Error(w, "Invalid task ID", http.StatusBadRequest)
+        writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+        return
+    }
+    if hard {
+        h.purgeTask(w, r, id)
+        return
+    }
+
+    // Evaluar la precondición If-Match contra la versión almacenada.
+    version, ok := h.checkIfMatch(w, r, id)
+    if !ok {
         return
     }
 
     // Eliminar la tarea del repositorio.
-    if err := h.repo.DeleteTask(uint(id)); err != nil {
-        log.Printf("Error deleting task: %v", err) // Registrar el error para depuración.
-        http.Error(w, "Error deleting task", http.StatusInternalServerError)
+    if err := h.repo.WithCaller(requestCaller(r)).DeleteTask(r.Context(), id, version); err != nil {
+        writeDomainError(w, r, err)
         return
     }
 
     // Responder con un código de estado 204 No Content.
     w.WriteHeader(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+// parseTaskID extrae el parámetro {id} de la URL.
+// Si no es un entero positivo responde 400 y retorna ok=false.
+func parseTaskID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+    id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 0)
+    if err != nil || id == 0 {
+        writeError(w, r, http.StatusBadRequest, CodeInvalidID, "Invalid task ID")
+        return 0, false
+    }
+    return uint(id), true
+}
+
+// defaultPriority asigna la prioridad media cuando el cliente no indica prioridad.
+func defaultPriority(task *models.Task) {
+    if task.Priority == "" {
+        task.Priority = models.PriorityMedium
+    }
+}
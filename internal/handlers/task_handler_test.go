@@ -0,0 +1,668 @@
+package handlers_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/handlers"
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// fakeTaskRepository repositorio de tareas cuyas respuestas define cada prueba
+// Los métodos sin función configurada hacen fallar la prueba: el handler no debería llamarlos.
+type fakeTaskRepository struct {
+	repository.TaskRepository // Métodos no usados por task_handler.go (nil)
+
+	t              *testing.T
+	createTask     func(task *models.Task) error
+	getTasks       func(opts repository.TaskQueryOptions) (*repository.TaskPage, error)
+	searchTasks    func(opts repository.SearchOptions) (*repository.SearchPage, error)
+	getTaskByID    func(id uint) (*models.Task, error)
+	updateTask     func(task *models.Task) error
+	patchTask      func(task *models.Task, columns []string) error
+	deleteTask     func(id, expectedVersion uint) error
+	getDeletedTask func(id uint) (*models.Task, error)
+	purgeTask      func(id, expectedVersion uint) error
+
+	caller repository.Caller // Último origen recibido en WithCaller
+	ctx    context.Context   // Contexto de la última llamada
+}
+
+func (f *fakeTaskRepository) unexpected(method string) {
+	f.t.Helper()
+	f.t.Fatalf("unexpected call to %s", method)
+}
+
+func (f *fakeTaskRepository) WithCaller(caller repository.Caller) repository.TaskRepository {
+	f.caller = caller
+	return f
+}
+
+func (f *fakeTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	f.ctx = ctx
+	if f.createTask == nil {
+		f.unexpected("CreateTask")
+	}
+	return f.createTask(task)
+}
+
+func (f *fakeTaskRepository) GetTasks(ctx context.Context, opts repository.TaskQueryOptions) (*repository.TaskPage, error) {
+	f.ctx = ctx
+	if f.getTasks == nil {
+		f.unexpected("GetTasks")
+	}
+	return f.getTasks(opts)
+}
+
+func (f *fakeTaskRepository) SearchTasks(ctx context.Context, opts repository.SearchOptions) (*repository.SearchPage, error) {
+	f.ctx = ctx
+	if f.searchTasks == nil {
+		f.unexpected("SearchTasks")
+	}
+	return f.searchTasks(opts)
+}
+
+func (f *fakeTaskRepository) GetTaskByID(ctx context.Context, id uint) (*models.Task, error) {
+	f.ctx = ctx
+	if f.getTaskByID == nil {
+		f.unexpected("GetTaskByID")
+	}
+	return f.getTaskByID(id)
+}
+
+func (f *fakeTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	f.ctx = ctx
+	if f.updateTask == nil {
+		f.unexpected("UpdateTask")
+	}
+	return f.updateTask(task)
+}
+
+func (f *fakeTaskRepository) PatchTask(ctx context.Context, task *models.Task, columns []string) error {
+	f.ctx = ctx
+	if f.patchTask == nil {
+		f.unexpected("PatchTask")
+	}
+	return f.patchTask(task, columns)
+}
+
+func (f *fakeTaskRepository) DeleteTask(ctx context.Context, id, expectedVersion uint) error {
+	f.ctx = ctx
+	if f.deleteTask == nil {
+		f.unexpected("DeleteTask")
+	}
+	return f.deleteTask(id, expectedVersion)
+}
+
+func (f *fakeTaskRepository) GetDeletedTask(ctx context.Context, id uint) (*models.Task, error) {
+	f.ctx = ctx
+	if f.getDeletedTask == nil {
+		f.unexpected("GetDeletedTask")
+	}
+	return f.getDeletedTask(id)
+}
+
+func (f *fakeTaskRepository) PurgeTask(ctx context.Context, id, expectedVersion uint) error {
+	f.ctx = ctx
+	if f.purgeTask == nil {
+		f.unexpected("PurgeTask")
+	}
+	return f.purgeTask(id, expectedVersion)
+}
+
+// storedTask tarea con ID y versión como la retornaría el repositorio
+func storedTask(id, version uint) *models.Task {
+	task := &models.Task{Name: fmt.Sprintf("Task %d", id), Status: models.ToDo, StatusCategory: models.CategoryTodo,
+		Priority: models.PriorityMedium, Version: version}
+	task.ID = id
+	return task
+}
+
+// found GetTaskByID que retorna la tarea si el ID coincide y ErrTaskNotFound si no
+func found(task *models.Task) func(uint) (*models.Task, error) {
+	return func(id uint) (*models.Task, error) {
+		if id != task.ID {
+			return nil, repository.ErrTaskNotFound
+		}
+		copy := *task
+		return &copy, nil
+	}
+}
+
+// serve ejecuta la solicitud contra las rutas de task_handler.go, registradas como en routes.SetupRoutes
+// Los headers se indican como pares nombre, valor.
+func serve(t *testing.T, repo *fakeTaskRepository, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	repo.t = t
+	h := handlers.NewTaskHandler(repo)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Post("/tasks", h.CreateTaskHandler)
+	r.Get("/tasks", h.GetTasksHandler)
+	r.Get("/tasks/search", h.SearchTasksHandler)
+	r.Post("/tasks/bulk", h.BulkTasksHandler)
+	r.Get("/tasks/{id}", h.GetTaskByIDHandler)
+	r.Put("/tasks/{id}", h.UpdateTaskHandler)
+	r.Patch("/tasks/{id}", h.PatchTaskHandler)
+	r.Delete("/tasks/{id}", h.DeleteTaskHandler)
+	r.Post("/projects/{pid}/tasks", h.CreateProjectTaskHandler)
+	r.Get("/projects/{pid}/tasks", h.GetProjectTasksHandler)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode deserializa el cuerpo JSON de la respuesta
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+}
+
+// expectProblem verifica el código HTTP y el código de error de una respuesta application/problem+json
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, status, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var p handlers.Problem
+	decode(t, rec, &p)
+	if p.Code != code || p.Status != status {
+		t.Errorf("problem = %+v, want code %q", p, code)
+	}
+}
+
+func TestCreateTaskHandler(t *testing.T) {
+	var created *models.Task
+	repo := &fakeTaskRepository{createTask: func(task *models.Task) error {
+		created = task
+		task.ID, task.Version, task.Status = 7, 1, models.ToDo
+		return nil
+	}}
+	rec := serve(t, repo, http.MethodPost, "/tasks", `{"name":"Write report","description":"Q3"}`, "X-Actor", "ana")
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", rec.Code, rec.Body.String())
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("ETag = %q, want %q", etag, `"1"`)
+	}
+	var body models.Task
+	decode(t, rec, &body)
+	if body.ID != 7 || body.Name != "Write report" || body.Status != models.ToDo {
+		t.Errorf("body = %+v", body)
+	}
+	if created.Priority != models.PriorityMedium || created.ProjectID != nil {
+		t.Errorf("repository received priority %q and project %v, want default priority and no project", created.Priority, created.ProjectID)
+	}
+	if repo.caller.Actor != "ana" {
+		t.Errorf("caller actor = %q, want ana", repo.caller.Actor)
+	}
+}
+
+func TestCreateTaskHandlerLongCaller(t *testing.T) {
+	repo := &fakeTaskRepository{createTask: func(task *models.Task) error {
+		task.ID, task.Version = 7, 1
+		return nil
+	}}
+	// Autor e ID de solicitud se recortan al tamaño de sus columnas en la auditoría
+	rec := serve(t, repo, http.MethodPost, "/tasks", `{"name":"Write report"}`,
+		"X-Request-Id", strings.Repeat("r", 300), "X-Actor", strings.Repeat("ñ", 300))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", rec.Code, rec.Body.String())
+	}
+	if repo.caller.RequestID != strings.Repeat("r", 100) {
+		t.Errorf("caller request ID has %d characters, want 100", len(repo.caller.RequestID))
+	}
+	if repo.caller.Actor != strings.Repeat("ñ", 100) {
+		t.Errorf("caller actor = %q, want 100 characters", repo.caller.Actor)
+	}
+}
+
+func TestCreateTaskHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error // Error del repositorio (nil = no se debe llamar)
+		status int
+		code   string
+	}{
+		{"invalid JSON", `{"name":`, nil, http.StatusBadRequest, handlers.CodeInvalidPayload},
+		{"missing name", `{"description":"no name"}`, nil, http.StatusUnprocessableEntity, handlers.CodeValidationFailed},
+		{"invalid priority", `{"name":"A","priority":"someday"}`, nil, http.StatusUnprocessableEntity, handlers.CodeValidationFailed},
+		{"duplicate name", `{"name":"A"}`, repository.ErrDuplicateName, http.StatusConflict, handlers.CodeDuplicateName},
+		{"missing parent", `{"name":"A","parent_id":9}`, models.ValidationErrors{{Field: "parent_id", Message: "parent task does not exist"}},
+			http.StatusUnprocessableEntity, handlers.CodeValidationFailed},
+		{"unexpected error", `{"name":"A"}`, errors.New("connection reset"), http.StatusInternalServerError, handlers.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTaskRepository{}
+			if tt.err != nil {
+				repo.createTask = func(*models.Task) error { return tt.err }
+			}
+			rec := serve(t, repo, http.MethodPost, "/tasks", tt.body)
+			expectProblem(t, rec, tt.status, tt.code)
+		})
+	}
+}
+
+func TestCreateProjectTaskHandler(t *testing.T) {
+	var projectID *uint
+	repo := &fakeTaskRepository{createTask: func(task *models.Task) error {
+		projectID = task.ProjectID
+		return nil
+	}}
+	rec := serve(t, repo, http.MethodPost, "/projects/3/tasks", `{"name":"A","project_id":99}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", rec.Code, rec.Body.String())
+	}
+	if projectID == nil || *projectID != 3 {
+		t.Errorf("repository received project %v, want the project of the URL (3)", projectID)
+	}
+
+	expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodPost, "/projects/x/tasks", `{"name":"A"}`),
+		http.StatusBadRequest, handlers.CodeInvalidID)
+
+	repo = &fakeTaskRepository{createTask: func(*models.Task) error { return repository.ErrProjectNotFound }}
+	expectProblem(t, serve(t, repo, http.MethodPost, "/projects/3/tasks", `{"name":"A"}`),
+		http.StatusNotFound, handlers.CodeProjectNotFound)
+}
+
+func TestGetTasksHandler(t *testing.T) {
+	var opts repository.TaskQueryOptions
+	repo := &fakeTaskRepository{getTasks: func(o repository.TaskQueryOptions) (*repository.TaskPage, error) {
+		opts = o
+		return &repository.TaskPage{Tasks: []models.Task{*storedTask(1, 2)}, Total: 5, Limit: 1, NextCursor: "next"}, nil
+	}}
+	rec := serve(t, repo, http.MethodGet, "/tasks?status=To+do&priority=high&tag=work&sort=-name&limit=1", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if len(opts.Status) != 1 || opts.Status[0] != models.ToDo || len(opts.Priority) != 1 || opts.Priority[0] != models.PriorityHigh ||
+		len(opts.Tags) != 1 || opts.Limit != 1 || len(opts.Sort) != 1 || !opts.Sort[0].Desc || opts.ProjectID != nil {
+		t.Errorf("repository received options %+v", opts)
+	}
+	var body struct {
+		Items      []models.Task `json:"items"`
+		Total      int64         `json:"total"`
+		NextCursor string        `json:"next_cursor"`
+	}
+	decode(t, rec, &body)
+	if len(body.Items) != 1 || body.Items[0].ID != 1 || body.Total != 5 || body.NextCursor != "next" {
+		t.Errorf("body = %+v", body)
+	}
+
+	// Misma página: el cliente puede reutilizar su copia
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q, want a weak ETag", etag)
+	}
+	rec = serve(t, repo, http.MethodGet, "/tasks?limit=1", "", "If-None-Match", etag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional request: status = %d with %d bytes, want 304 without body", rec.Code, rec.Body.Len())
+	}
+}
+
+func TestGetTasksHandlerEmptyPage(t *testing.T) {
+	repo := &fakeTaskRepository{getTasks: func(repository.TaskQueryOptions) (*repository.TaskPage, error) {
+		return &repository.TaskPage{Limit: 50}, nil
+	}}
+	rec := serve(t, repo, http.MethodGet, "/tasks", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"items":[]`) {
+		t.Errorf("status = %d, body %s; want 200 with an empty items array", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetTasksHandlerErrors(t *testing.T) {
+	for _, target := range []string{"/tasks?limit=-1", "/tasks?priority=someday", "/tasks?sort=color", "/tasks?overdue=maybe"} {
+		expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodGet, target, ""), http.StatusBadRequest, handlers.CodeInvalidQuery)
+	}
+
+	repo := &fakeTaskRepository{getTasks: func(repository.TaskQueryOptions) (*repository.TaskPage, error) {
+		return nil, fmt.Errorf("%w: invalid cursor", repository.ErrInvalidQuery)
+	}}
+	expectProblem(t, serve(t, repo, http.MethodGet, "/tasks?cursor=abc", ""), http.StatusBadRequest, handlers.CodeInvalidQuery)
+}
+
+func TestGetProjectTasksHandler(t *testing.T) {
+	var projectID *uint
+	repo := &fakeTaskRepository{getTasks: func(o repository.TaskQueryOptions) (*repository.TaskPage, error) {
+		projectID = o.ProjectID
+		return &repository.TaskPage{}, nil
+	}}
+	if rec := serve(t, repo, http.MethodGet, "/projects/4/tasks", ""); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if projectID == nil || *projectID != 4 {
+		t.Errorf("repository received project %v, want 4", projectID)
+	}
+
+	expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodGet, "/projects/0/tasks", ""), http.StatusBadRequest, handlers.CodeInvalidID)
+
+	repo = &fakeTaskRepository{getTasks: func(repository.TaskQueryOptions) (*repository.TaskPage, error) {
+		return nil, repository.ErrProjectNotFound
+	}}
+	expectProblem(t, serve(t, repo, http.MethodGet, "/projects/4/tasks", ""), http.StatusNotFound, handlers.CodeProjectNotFound)
+}
+
+func TestSearchTasksHandler(t *testing.T) {
+	var opts repository.SearchOptions
+	repo := &fakeTaskRepository{searchTasks: func(o repository.SearchOptions) (*repository.SearchPage, error) {
+		opts = o
+		return &repository.SearchPage{Total: 1, Limit: 10, Offset: 5, Results: []repository.SearchResult{{
+			Task:          *storedTask(2, 1),
+			Rank:          0.6,
+			NameHighlight: "Write <mark>report</mark>",
+		}}}, nil
+	}}
+	rec := serve(t, repo, http.MethodGet, "/tasks/search?q=report&limit=10&offset=5", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if opts.Query != "report" || opts.Limit != 10 || opts.Offset != 5 {
+		t.Errorf("repository received options %+v", opts)
+	}
+	var body struct {
+		Items []struct {
+			Task       models.Task `json:"task"`
+			Rank       float64     `json:"rank"`
+			Highlights struct {
+				Name string `json:"name"`
+			} `json:"highlights"`
+		} `json:"items"`
+		Total int64 `json:"total"`
+	}
+	decode(t, rec, &body)
+	if body.Total != 1 || len(body.Items) != 1 || body.Items[0].Task.ID != 2 || body.Items[0].Rank != 0.6 ||
+		body.Items[0].Highlights.Name != "Write <mark>report</mark>" {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestSearchTasksHandlerErrors(t *testing.T) {
+	for _, target := range []string{"/tasks/search", "/tasks/search?q=a&limit=x"} {
+		expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodGet, target, ""), http.StatusBadRequest, handlers.CodeInvalidQuery)
+	}
+
+	repo := &fakeTaskRepository{searchTasks: func(repository.SearchOptions) (*repository.SearchPage, error) {
+		return nil, fmt.Errorf("%w: search query must contain at least one word", repository.ErrInvalidQuery)
+	}}
+	expectProblem(t, serve(t, repo, http.MethodGet, "/tasks/search?q=%3F%21", ""), http.StatusBadRequest, handlers.CodeInvalidQuery)
+}
+
+func TestGetTaskByIDHandler(t *testing.T) {
+	repo := &fakeTaskRepository{getTaskByID: found(storedTask(5, 3))}
+	rec := serve(t, repo, http.MethodGet, "/tasks/5", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("ETag = %q, want %q", etag, `"3"`)
+	}
+	var body models.Task
+	decode(t, rec, &body)
+	if body.ID != 5 || body.Version != 3 {
+		t.Errorf("body = %+v", body)
+	}
+
+	rec = serve(t, repo, http.MethodGet, "/tasks/5", "", "If-None-Match", `"3"`)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match with the current ETag: status = %d, want 304", rec.Code)
+	}
+	if rec = serve(t, repo, http.MethodGet, "/tasks/5", "", "If-None-Match", `"2"`); rec.Code != http.StatusOK {
+		t.Errorf("If-None-Match with an old ETag: status = %d, want 200", rec.Code)
+	}
+
+	expectProblem(t, serve(t, repo, http.MethodGet, "/tasks/6", ""), http.StatusNotFound, handlers.CodeTaskNotFound)
+	expectProblem(t, serve(t, repo, http.MethodGet, "/tasks/abc", ""), http.StatusBadRequest, handlers.CodeInvalidID)
+}
+
+func TestGetTaskByIDHandlerQueryTimeout(t *testing.T) {
+	repo := &fakeTaskRepository{t: t, getTaskByID: found(storedTask(5, 1))}
+	r := chi.NewRouter()
+	r.With(handlers.QueryTimeout(time.Minute)).Get("/tasks/{id}", handlers.NewTaskHandler(repo).GetTaskByIDHandler)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/5", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if deadline, ok := repo.ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("repository context deadline = %v, %v; want the request deadline", deadline, ok)
+	}
+}
+
+func TestGetTaskByIDHandlerUnavailable(t *testing.T) {
+	repo := &fakeTaskRepository{getTaskByID: func(id uint) (*models.Task, error) {
+		return nil, fmt.Errorf("task with ID %d: %w", id, context.DeadlineExceeded)
+	}}
+	expectProblem(t, serve(t, repo, http.MethodGet, "/tasks/5", ""), http.StatusGatewayTimeout, handlers.CodeQueryTimeout)
+
+	for _, err := range []error{context.Canceled, driver.ErrBadConn} {
+		repo.getTaskByID = func(uint) (*models.Task, error) { return nil, err }
+		rec := serve(t, repo, http.MethodGet, "/tasks/5", "")
+		expectProblem(t, rec, http.StatusServiceUnavailable, handlers.CodeUnavailable)
+		if rec.Header().Get("Retry-After") == "" {
+			t.Errorf("%v: missing Retry-After header", err)
+		}
+	}
+}
+
+func TestUpdateTaskHandler(t *testing.T) {
+	var updated *models.Task
+	var expected uint
+	repo := &fakeTaskRepository{
+		getTaskByID: found(storedTask(5, 3)),
+		updateTask: func(task *models.Task) error {
+			updated, expected = task, task.Version
+			task.Version = 4
+			return nil
+		},
+	}
+	rec := serve(t, repo, http.MethodPut, "/tasks/5", `{"id":9,"name":"Renamed","status":"In progress"}`, "If-Match", `"3"`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if updated.ID != 5 || expected != 3 || updated.Name != "Renamed" || updated.Priority != models.PriorityMedium {
+		t.Errorf("repository received %+v (version %d), want the task of the URL, version 3 and the default priority", updated, expected)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"4"` {
+		t.Errorf("ETag = %q, want %q", etag, `"4"`)
+	}
+
+	// Sin If-Match la escritura no comprueba la versión
+	if rec := serve(t, repo, http.MethodPut, "/tasks/5", `{"name":"Renamed"}`); rec.Code != http.StatusOK || expected != 0 {
+		t.Errorf("update without If-Match: status = %d, expected version %d; want 200 and 0", rec.Code, expected)
+	}
+}
+
+func TestUpdateTaskHandlerPreconditions(t *testing.T) {
+	repo := &fakeTaskRepository{getTaskByID: found(storedTask(5, 3))}
+	// La versión esperada pasa al repositorio, que detecta los cambios concurrentes
+	repo.updateTask = func(task *models.Task) error {
+		if task.Version != 3 {
+			t.Errorf("expected version = %d, want 3", task.Version)
+		}
+		return repository.ErrVersionConflict
+	}
+
+	expectProblem(t, serve(t, repo, http.MethodPut, "/tasks/5", `{"name":"A"}`, "If-Match", `"3"`),
+		http.StatusPreconditionFailed, handlers.CodePreconditionFailed)
+
+	repo.updateTask = nil // Con un ETag desactualizado no se llega a escribir
+	expectProblem(t, serve(t, repo, http.MethodPut, "/tasks/5", `{"name":"A"}`, "If-Match", `"2"`),
+		http.StatusPreconditionFailed, handlers.CodePreconditionFailed)
+	expectProblem(t, serve(t, repo, http.MethodPut, "/tasks/6", `{"name":"A"}`, "If-Match", `"2"`),
+		http.StatusNotFound, handlers.CodeTaskNotFound)
+}
+
+func TestUpdateTaskHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		err    error // Error del repositorio (nil = no se debe llamar)
+		status int
+		code   string
+	}{
+		{"invalid ID", "/tasks/-1", `{"name":"A"}`, nil, http.StatusBadRequest, handlers.CodeInvalidID},
+		{"invalid JSON", "/tasks/5", `[`, nil, http.StatusBadRequest, handlers.CodeInvalidPayload},
+		{"missing name", "/tasks/5", `{"name":" "}`, nil, http.StatusUnprocessableEntity, handlers.CodeValidationFailed},
+		{"not found", "/tasks/5", `{"name":"A"}`, repository.ErrTaskNotFound, http.StatusNotFound, handlers.CodeTaskNotFound},
+		{"duplicate name", "/tasks/5", `{"name":"A"}`, repository.ErrDuplicateName, http.StatusConflict, handlers.CodeDuplicateName},
+		{"hierarchy cycle", "/tasks/5", `{"name":"A","parent_id":5}`, repository.ErrHierarchyCycle, http.StatusConflict, handlers.CodeHierarchyCycle},
+		{"invalid transition", "/tasks/5", `{"name":"A","status":"Completed"}`, repository.ErrInvalidTransition, http.StatusConflict, handlers.CodeInvalidTransition},
+		{"blocked", "/tasks/5", `{"name":"A","status":"In progress"}`, repository.ErrTaskBlocked, http.StatusConflict, handlers.CodeTaskBlocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTaskRepository{}
+			if tt.err != nil {
+				repo.updateTask = func(*models.Task) error { return tt.err }
+			}
+			expectProblem(t, serve(t, repo, http.MethodPut, tt.target, tt.body), tt.status, tt.code)
+		})
+	}
+}
+
+func TestDeleteTaskHandler(t *testing.T) {
+	var deleted, expected uint
+	repo := &fakeTaskRepository{
+		getTaskByID: found(storedTask(5, 3)),
+		deleteTask: func(id, expectedVersion uint) error {
+			deleted, expected = id, expectedVersion
+			return nil
+		},
+	}
+
+	rec := serve(t, repo, http.MethodDelete, "/tasks/5", "")
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Fatalf("status = %d, want 204 without body (body %s)", rec.Code, rec.Body.String())
+	}
+	if deleted != 5 || expected != 0 {
+		t.Errorf("repository deleted %d with version %d, want 5 without version check", deleted, expected)
+	}
+
+	if rec := serve(t, repo, http.MethodDelete, "/tasks/5", "", "If-Match", `"3"`); rec.Code != http.StatusNoContent || expected != 3 {
+		t.Errorf("delete with If-Match: status = %d, expected version %d; want 204 and 3", rec.Code, expected)
+	}
+
+	repo.deleteTask = nil // Con un ETag desactualizado no se llega a eliminar
+	expectProblem(t, serve(t, repo, http.MethodDelete, "/tasks/5", "", "If-Match", `"1"`),
+		http.StatusPreconditionFailed, handlers.CodePreconditionFailed)
+	expectProblem(t, serve(t, repo, http.MethodDelete, "/tasks/abc", ""), http.StatusBadRequest, handlers.CodeInvalidID)
+	expectProblem(t, serve(t, repo, http.MethodDelete, "/tasks/5?hard=maybe", ""), http.StatusBadRequest, handlers.CodeInvalidQuery)
+
+	repo.deleteTask = func(uint, uint) error { return repository.ErrTaskNotFound }
+	expectProblem(t, serve(t, repo, http.MethodDelete, "/tasks/6", ""), http.StatusNotFound, handlers.CodeTaskNotFound)
+}
+
+func TestDeleteTaskHandlerHard(t *testing.T) {
+	var purged, expected uint
+	trashed := storedTask(8, 2)
+	repo := &fakeTaskRepository{
+		getTaskByID:    func(uint) (*models.Task, error) { return nil, repository.ErrTaskNotFound },
+		getDeletedTask: found(trashed),
+		purgeTask: func(id, expectedVersion uint) error {
+			purged, expected = id, expectedVersion
+			return nil
+		},
+	}
+
+	// Una tarea en la papelera también se puede eliminar definitivamente, con su ETag
+	rec := serve(t, repo, http.MethodDelete, "/tasks/8?hard=true", "", "If-Match", `"2"`)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204 (body %s)", rec.Code, rec.Body.String())
+	}
+	if purged != 8 || expected != 2 {
+		t.Errorf("repository purged %d with version %d, want 8 and 2", purged, expected)
+	}
+
+	expectProblem(t, serve(t, repo, http.MethodDelete, "/tasks/9?hard=true", "", "If-Match", `"2"`),
+		http.StatusNotFound, handlers.CodeTaskNotFound)
+}
+
+func TestBulkTasksHandlerBodyTooLarge(t *testing.T) {
+	// Un cuerpo mayor que el límite se rechaza en lugar de truncarse
+	body := `{"operations": [` + strings.Repeat(" ", 8<<20) + `{"op": "delete", "id": 1}]}`
+	expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodPost, "/tasks/bulk", body),
+		http.StatusRequestEntityTooLarge, handlers.CodePayloadTooLarge)
+
+	expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodPost, "/tasks/bulk", `{"operations": [`),
+		http.StatusBadRequest, handlers.CodeInvalidPayload)
+}
+
+func TestPatchTaskHandlerBodyTooLarge(t *testing.T) {
+	body := `{"description": "` + strings.Repeat("x", 1<<20) + `"}`
+	expectProblem(t, serve(t, &fakeTaskRepository{}, http.MethodPatch, "/tasks/1", body,
+		"Content-Type", "application/merge-patch+json"),
+		http.StatusRequestEntityTooLarge, handlers.CodePayloadTooLarge)
+}
+
+func TestPatchTaskHandlerConcurrentUpdate(t *testing.T) {
+	// Otra solicitud incrementa la versión entre cada lectura y la escritura del patch
+	version := uint(1)
+	var attempts int
+	conflicts := 1
+	repo := &fakeTaskRepository{
+		getTaskByID: func(id uint) (*models.Task, error) {
+			version++
+			return storedTask(id, version), nil
+		},
+		patchTask: func(task *models.Task, columns []string) error {
+			attempts++
+			if attempts <= conflicts {
+				return repository.ErrVersionConflict
+			}
+			task.Version++
+			return nil
+		},
+	}
+	patch := func(headers ...string) *httptest.ResponseRecorder {
+		return serve(t, repo, http.MethodPatch, "/tasks/1", `{"description": "patched"}`,
+			append([]string{"Content-Type", "application/merge-patch+json"}, headers...)...)
+	}
+
+	// Sin If-Match el patch se vuelve a aplicar sobre la versión nueva
+	rec := patch()
+	if rec.Code != http.StatusOK || attempts != 2 {
+		t.Fatalf("status = %d after %d attempts, want 200 after 2 (body %s)", rec.Code, attempts, rec.Body.String())
+	}
+
+	// Con If-Match la versión se exige
+	attempts, version = 0, 1
+	expectProblem(t, patch("If-Match", `"2"`), http.StatusPreconditionFailed, handlers.CodePreconditionFailed)
+
+	// Si los conflictos continúan se responde 409 en lugar de reintentar indefinidamente
+	attempts, conflicts = 0, 10
+	expectProblem(t, patch(), http.StatusConflict, handlers.CodeConflict)
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
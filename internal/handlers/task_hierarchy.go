@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// subtaskItem elemento de la respuesta de GET /tasks/{id}/subtasks
+type subtaskItem struct {
+	Task     models.Task `json:"task"`
+	Progress float64     `json:"progress"` // Progreso acumulado de la subtarea (0-100)
+	Subtasks int         `json:"subtasks"` // Cantidad de subtareas directas de la subtarea
+}
+
+// subtasksResponse cuerpo de respuesta de GET /tasks/{id}/subtasks
+type subtasksResponse struct {
+	ParentID uint          `json:"parent_id"`
+	Progress float64       `json:"progress"` // Progreso acumulado de la tarea padre (0-100)
+	Items    []subtaskItem `json:"items"`
+}
+
+// GetSubtasksHandler maneja la obtención de las subtareas directas de una tarea.
+// Método HTTP: GET
+// Ruta: /tasks/{id}/subtasks
+func (h *taskHandler) GetSubtasksHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	tree, err := h.repo.GetTaskTree(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	resp := subtasksResponse{ParentID: id, Progress: tree.Progress, Items: []subtaskItem{}}
+	for _, child := range tree.Children {
+		resp.Items = append(resp.Items, subtaskItem{
+			Task:     child.Task,
+			Progress: child.Progress,
+			Subtasks: len(child.Children),
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetTaskTreeHandler maneja la obtención de la jerarquía completa de subtareas de una tarea.
+// Método HTTP: GET
+// Ruta: /tasks/{id}/tree
+func (h *taskHandler) GetTaskTreeHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	tree, err := h.repo.GetTaskTree(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tree)
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import "net/http"
+
+// GetTaskHistoryHandler maneja la obtención del historial de estados de una tarea
+// con su lead time, cycle time y tiempo acumulado en cada estado.
+// Método HTTP: GET
+// Ruta: /tasks/{id}/history
+func (h *taskHandler) GetTaskHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.repo.GetTaskHistory(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
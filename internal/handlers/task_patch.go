@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Tipos de contenido admitidos por PATCH /tasks/{id}
+const (
+	mergePatchContentType = "application/merge-patch+json" // RFC 7396
+	jsonPatchContentType  = "application/json-patch+json"  // RFC 6902
+)
+
+// maxPatchBodySize tamaño máximo aceptado para el cuerpo de un PATCH
+const maxPatchBodySize = 1 << 20
+
+// maxPatchAttempts veces que se aplica un PATCH sin If-Match cuando otra solicitud modifica la tarea al mismo tiempo
+const maxPatchAttempts = 3
+
+// Códigos de error específicos de PATCH
+const (
+	CodeUnsupportedMediaType = "unsupported_media_type" // Content-Type distinto de los formatos de patch
+	CodePatchConflict        = "patch_conflict"         // El patch no se puede aplicar al estado actual
+)
+
+// taskDocument representación JSON de los campos modificables de una tarea.
+// Los patches se aplican sobre este documento, de modo que rutas como /ID o /CreatedAt
+// no existen y no pueden modificarse.
+type taskDocument struct {
+	ProjectID   *uint           `json:"project_id"`
+	ParentID    *uint           `json:"parent_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Status      models.Status   `json:"status"`
+	Priority    models.Priority `json:"priority"`
+	StartAt     *time.Time      `json:"start_at"`
+	DueAt       *time.Time      `json:"due_at"`
+}
+
+// newTaskDocument extrae los campos modificables de la tarea
+func newTaskDocument(t *models.Task) taskDocument {
+	return taskDocument{
+		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		Name:        t.Name,
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		StartAt:     t.StartAt,
+		DueAt:       t.DueAt,
+	}
+}
+
+// applyTo copia los campos del documento sobre la tarea
+func (d taskDocument) applyTo(t *models.Task) {
+	t.ProjectID = d.ProjectID
+	t.ParentID = d.ParentID
+	t.Name = d.Name
+	t.Description = d.Description
+	t.Status = d.Status
+	t.Priority = d.Priority
+	t.StartAt = d.StartAt
+	t.DueAt = d.DueAt
+}
+
+// sameTime compara dos fechas opcionales (ambas nil o el mismo instante)
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// sameID compara dos IDs opcionales (ambos nil o el mismo valor)
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// changedColumns retorna las columnas cuyo valor difiere entre el documento original y el nuevo
+func (d taskDocument) changedColumns(original taskDocument) []string {
+	var columns []string
+	if !sameID(d.ProjectID, original.ProjectID) {
+		columns = append(columns, "project_id")
+	}
+	if !sameID(d.ParentID, original.ParentID) {
+		columns = append(columns, "parent_id")
+	}
+	if d.Name != original.Name {
+		columns = append(columns, "name")
+	}
+	if d.Description != original.Description {
+		columns = append(columns, "description")
+	}
+	if d.Status != original.Status {
+		columns = append(columns, "status")
+	}
+	if d.Priority != original.Priority {
+		columns = append(columns, "priority")
+	}
+	if !sameTime(d.StartAt, original.StartAt) {
+		columns = append(columns, "start_at")
+	}
+	if !sameTime(d.DueAt, original.DueAt) {
+		columns = append(columns, "due_at")
+	}
+	return columns
+}
+
+// errPatchConflict indica que el patch es válido pero no puede aplicarse al documento actual
+var errPatchConflict = errors.New("patch cannot be applied")
+
+// applyPatch aplica el cuerpo del patch al documento según el tipo de contenido
+// Retorna: el documento resultante en JSON, o errPatchConflict si falla una operación
+func applyPatch(contentType string, doc, patch []byte) ([]byte, error) {
+	switch contentType {
+	case mergePatchContentType:
+		if !json.Valid(patch) || !bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{")) {
+			return nil, fmt.Errorf("merge patch must be a JSON object")
+		}
+		return jsonpatch.MergePatch(doc, patch)
+	default:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON patch: %v", err)
+		}
+		patched, err := ops.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errPatchConflict, err)
+		}
+		return patched, nil
+	}
+}
+
+// decodeTaskDocument convierte el documento resultante en taskDocument rechazando campos desconocidos
+func decodeTaskDocument(data []byte) (taskDocument, error) {
+	var doc taskDocument
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return doc, models.ValidationErrors{{Field: "document", Message: strings.TrimPrefix(err.Error(), "json: ")}}
+	}
+	return doc, nil
+}
+
+// patchTask aplica el patch sobre el documento de campos modificables de la tarea y valida el resultado
+// Retorna: las columnas que cambiaron, errPatchConflict, un error de formato del patch o ValidationErrors
+func patchTask(task *models.Task, contentType string, patch []byte) ([]string, error) {
+	original := newTaskDocument(task)
+	originalJSON, _ := json.Marshal(original)
+	patchedJSON, err := applyPatch(contentType, originalJSON, patch)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := decodeTaskDocument(patchedJSON)
+	if err != nil {
+		return nil, err
+	}
+	patched.applyTo(task)
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	return patched.changedColumns(original), nil
+}
+
+// PatchTaskHandler maneja la actualización parcial de una tarea.
+// Método HTTP: PATCH
+// Ruta: /tasks/{id}
+// Content-Type: application/merge-patch+json (RFC 7396) o application/json-patch+json (RFC 6902)
+// Header opcional If-Match: el patch solo se aplica si coincide con el ETag actual (412 si no).
+// Sin If-Match, si otra solicitud modifica la tarea antes de guardar el patch, este se vuelve a aplicar
+// sobre el nuevo estado (hasta maxPatchAttempts veces; después se responde 409).
+func (h *taskHandler) PatchTaskHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close() // Cerrar el cuerpo de la solicitud al finalizar.
+
+	// Extraer el ID de la URL.
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	// Verificar que el formato del patch sea uno de los soportados.
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != mergePatchContentType && contentType != jsonPatchContentType {
+		w.Header().Set("Accept-Patch", mergePatchContentType+", "+jsonPatchContentType)
+		writeError(w, r, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType,
+			"Content-Type must be "+mergePatchContentType+" or "+jsonPatchContentType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchBodySize))
+	if err != nil {
+		writeBodyError(w, r, err, "Error reading request body")
+		return
+	}
+
+	precondition := r.Header.Get("If-Match") != ""
+	for attempt := 1; ; attempt++ {
+		// Obtener el estado actual de la tarea y evaluar If-Match.
+		// La versión leída se usa como versión esperada al escribir, de modo que
+		// una modificación concurrente entre la lectura y la escritura no se pierda.
+		task, err := h.repo.GetTaskByID(r.Context(), id)
+		if err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+		if !ifMatch(r, taskETag(task)) {
+			writePreconditionFailed(w, r)
+			return
+		}
+
+		// Aplicar el patch sobre el documento de campos modificables y validar la tarea resultante.
+		columns, err := patchTask(task, contentType, body)
+		var validationErrs models.ValidationErrors
+		switch {
+		case errors.Is(err, errPatchConflict):
+			writeError(w, r, http.StatusConflict, CodePatchConflict, err.Error())
+			return
+		case errors.As(err, &validationErrs):
+			writeValidationError(w, r, validationErrs)
+			return
+		case err != nil:
+			writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, err.Error())
+			return
+		}
+
+		// Persistir solo las columnas que cambiaron.
+		if len(columns) > 0 {
+			err := h.repo.WithCaller(requestCaller(r)).PatchTask(r.Context(), task, columns)
+			if errors.Is(err, repository.ErrVersionConflict) && !precondition {
+				// El cliente no exigió una versión: aplicar el patch sobre el estado nuevo
+				if attempt < maxPatchAttempts {
+					continue
+				}
+				writeError(w, r, http.StatusConflict, CodeConflict,
+					"The task is being modified by other requests; try again")
+				return
+			}
+			if err != nil {
+				writeDomainError(w, r, err)
+				return
+			}
+		}
+
+		// Responder con un código de estado 200 OK y devolver la tarea actualizada como JSON.
+		w.Header().Set("ETag", taskETag(task))
+		writeJSON(w, http.StatusOK, task)
+		return
+	}
+}
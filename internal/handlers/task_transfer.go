@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/formats"
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// Límites de POST /tasks/import
+const (
+	maxImportBodySize = 32 << 20 // Tamaño máximo del archivo
+	maxImportRows     = 10000    // Filas por archivo
+)
+
+// defaultExportFormat formato de GET /tasks/export si no se indica format
+const defaultExportFormat = "csv"
+
+// Modos de importación
+const (
+	importModeCreate = "create" // Cada fila crea una tarea
+	importModeUpsert = "upsert" // Una fila con el nombre de una tarea existente del proyecto la modifica
+)
+
+// CodePayloadTooLarge el cuerpo o el archivo supera el tamaño o el número de filas permitido
+const CodePayloadTooLarge = "payload_too_large"
+
+// importResponse cuerpo de respuesta de POST /tasks/import
+type importResponse struct {
+	DryRun  bool             `json:"dry_run"`
+	Mode    string           `json:"mode"`
+	Total   int              `json:"total"`   // Filas leídas
+	Created int              `json:"created"` // Tareas creadas (o que se crearían, en dry_run)
+	Updated int              `json:"updated"` // Tareas modificadas (solo en modo upsert)
+	Failed  int              `json:"failed"`  // Filas con error
+	Errors  []importRowError `json:"errors"`
+}
+
+// importRowError error de una fila del archivo
+type importRowError struct {
+	Row   int      `json:"row"` // Número de fila (en CSV la cabecera es la fila 1; en JSON, la posición del elemento)
+	Error *Problem `json:"error"`
+}
+
+// ExportTasksHandler maneja la exportación de todas las tareas que cumplen los filtros del listado.
+// La respuesta se escribe a medida que se leen las páginas de la base de datos.
+// Método HTTP: GET
+// Ruta: /tasks/export?format=csv|json|ndjson (además, los filtros y el ordenamiento de /tasks;
+// limit, offset y cursor se ignoran)
+func (h *taskHandler) ExportTasksHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = defaultExportFormat
+	}
+	format, ok := formats.Lookup(name)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery,
+			fmt.Sprintf("invalid format %q: must be one of %s", name, strings.Join(formats.Names(), ", ")))
+		return
+	}
+	opts, err := parseTaskQuery(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+		return
+	}
+	opts.Limit, opts.Offset, opts.Cursor = repository.MaxPageSize, 0, ""
+
+	// La primera página se lee antes de escribir los headers para poder responder con un error
+	page, err := h.repo.GetTasks(r.Context(), opts)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format.Extension))
+	w.WriteHeader(http.StatusOK)
+
+	// Un error posterior ya no puede informarse con un código HTTP, por lo que solo se registra en el log
+	if err := exportTasks(r.Context(), h.repo, opts, page, format.NewEncoder(w)); err != nil {
+		log.Printf("Error exporting tasks: %v", err)
+	}
+}
+
+// exportTasks escribe la página inicial y las siguientes, recorriéndolas con el cursor
+func exportTasks(ctx context.Context, repo repository.TaskRepository, opts repository.TaskQueryOptions, page *repository.TaskPage, enc formats.Encoder) error {
+	for {
+		for i := range page.Tasks {
+			if err := enc.Encode(&page.Tasks[i]); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return enc.Close()
+		}
+		opts.Cursor = page.NextCursor
+		var err error
+		if page, err = repo.GetTasks(ctx, opts); err != nil {
+			return err
+		}
+	}
+}
+
+// ImportTasksHandler maneja la importación de tareas desde un archivo CSV, JSON o NDJSON.
+// Las filas válidas se aplican y las inválidas se informan en errors con su número de fila;
+// con dry_run=true se valida todo el archivo sin guardar cambios.
+// Método HTTP: POST
+// Ruta: /tasks/import?format=&mode=create|upsert&dry_run=
+// Cuerpo: el archivo (el formato se toma de format o del Content-Type), o multipart/form-data con el
+// archivo en el campo file (el formato se toma de format, de la extensión o del Content-Type de la parte).
+func (h *taskHandler) ImportTasksHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	q := r.URL.Query()
+	mode := q.Get("mode")
+	if mode == "" {
+		mode = importModeCreate
+	}
+	if mode != importModeCreate && mode != importModeUpsert {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery,
+			fmt.Sprintf("invalid mode %q: must be %s or %s", mode, importModeCreate, importModeUpsert))
+		return
+	}
+	dryRun, err := parseBoolParam(q.Get("dry_run"), "dry_run")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+		return
+	}
+	if name := q.Get("format"); name != "" {
+		if _, ok := formats.Lookup(name); !ok {
+			writeError(w, r, http.StatusBadRequest, CodeInvalidQuery,
+				fmt.Sprintf("invalid format %q: must be one of %s", name, strings.Join(formats.Names(), ", ")))
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	file, format, err := importFile(r)
+	if err != nil {
+		writeImportError(w, r, err)
+		return
+	}
+	if format == nil {
+		writeError(w, r, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType,
+			"Cannot determine the file format: use the format parameter ("+strings.Join(formats.Names(), ", ")+")")
+		return
+	}
+
+	// Leer todas las filas; las que no se pueden interpretar se informan sin enviarlas al repositorio
+	resp := importResponse{DryRun: dryRun, Mode: mode, Errors: []importRowError{}}
+	var ops []repository.BulkOperation
+	var lines []int // Número de fila de cada operación
+	dec := format.NewDecoder(file)
+	for {
+		row, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeImportError(w, r, err)
+			return
+		}
+		if resp.Total++; resp.Total > maxImportRows {
+			writeError(w, r, http.StatusRequestEntityTooLarge, CodePayloadTooLarge,
+				fmt.Sprintf("The file must contain at most %d rows", maxImportRows))
+			return
+		}
+		op := repository.BulkOperation{}
+		if row.Err == nil {
+			op, row.Err = importOperation(mode, row)
+		}
+		if row.Err != nil {
+			resp.Errors = append(resp.Errors, importRowError{Row: row.Line, Error: domainProblem(r, row.Err)})
+			continue
+		}
+		ops = append(ops, op)
+		lines = append(lines, row.Line)
+	}
+
+	if len(ops) > 0 {
+		opts := repository.BulkOptions{DryRun: dryRun}
+		results, err := h.repo.WithCaller(requestCaller(r)).BulkTasks(r.Context(), ops, opts)
+		if err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+		for i, res := range results {
+			switch {
+			case res.Err != nil:
+				resp.Errors = append(resp.Errors, importRowError{Row: lines[i], Error: domainProblem(r, res.Err)})
+			case res.Action == repository.BulkCreate:
+				resp.Created++
+			default:
+				resp.Updated++
+			}
+		}
+	}
+	// Los errores de lectura y los del repositorio se informan juntos en el orden del archivo
+	sort.SliceStable(resp.Errors, func(i, j int) bool { return resp.Errors[i].Row < resp.Errors[j].Row })
+	resp.Failed = len(resp.Errors)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// importFile obtiene el archivo del cuerpo de la solicitud y detecta su formato
+// Retorna: el archivo, su formato (nil si no se pudo determinar) o error si el cuerpo multipart es inválido
+func importFile(r *http.Request) (io.Reader, *formats.Format, error) {
+	name := r.URL.Query().Get("format")
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		format, ok := formats.Lookup(name)
+		if !ok {
+			format, ok = formats.ByContentType(r.Header.Get("Content-Type"))
+		}
+		if !ok {
+			return r.Body, nil, nil
+		}
+		return r.Body, format, nil
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("missing file field in multipart form")
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		format, ok := formats.Lookup(name)
+		if !ok {
+			format, ok = formats.ByFilename(part.FileName())
+		}
+		if !ok {
+			format, ok = formats.ByContentType(part.Header.Get("Content-Type"))
+		}
+		if !ok {
+			return part, nil, nil
+		}
+		return part, format, nil
+	}
+}
+
+// writeImportError responde 413 si el archivo supera el tamaño permitido, o 400 si no se pudo leer
+func writeImportError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		writeError(w, r, http.StatusRequestEntityTooLarge, CodePayloadTooLarge,
+			fmt.Sprintf("The file must be at most %d bytes", maxErr.Limit))
+		return
+	}
+	writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, "Invalid file: "+err.Error())
+}
+
+// importOperation convierte los campos de una fila en la operación del lote
+//   - create: crea la tarea con los campos de la fila (mismas reglas que POST /tasks)
+//   - upsert: si el proyecto ya tiene una tarea con ese nombre, le aplica los campos de la fila
+//     como JSON Merge Patch; si no, la crea
+//
+// Si el formato informa etiquetas, la tarea queda con exactamente esas etiquetas.
+func importOperation(mode string, row *formats.Row) (repository.BulkOperation, error) {
+	var task models.Task
+	if err := json.Unmarshal(row.Fields, &task); err != nil {
+		return repository.BulkOperation{}, models.ValidationErrors{{Field: "row", Message: strings.TrimPrefix(err.Error(), "json: ")}}
+	}
+	defaultPriority(&task)
+	if mode == importModeCreate {
+		return repository.BulkOperation{Action: repository.BulkCreate, Task: &task, Tags: row.Tags, Category: row.Category}, nil
+	}
+	fields := row.Fields
+	return repository.BulkOperation{
+		Action:   repository.BulkUpsert,
+		Task:     &task,
+		Tags:     row.Tags,
+		Category: row.Category,
+		Apply: func(existing *models.Task) ([]string, error) {
+			return patchTask(existing, mergePatchContentType, fields)
+		},
+	}, nil
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// restoreRequest cuerpo opcional de POST /tasks/{id}/restore
+type restoreRequest struct {
+	Name string `json:"name"` // Nuevo nombre, para restaurar cuando otra tarea del proyecto ya usa el original
+}
+
+// GetTrashHandler maneja la obtención paginada de las tareas eliminadas, de la más reciente a la más antigua.
+// Las tareas permanecen en la papelera hasta que se restauran, se eliminan con hard=true o se cumple el
+// periodo de retención.
+// Método HTTP: GET
+// Ruta: /tasks/trash?limit=&offset=
+func (h *taskHandler) GetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	var opts repository.TrashOptions
+	var err error
+	q := r.URL.Query()
+	if opts.Limit, err = parseIntParam(q.Get("limit"), "limit"); err == nil {
+		opts.Offset, err = parseIntParam(q.Get("offset"), "offset")
+	}
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+		return
+	}
+
+	page, err := h.repo.GetTrash(r.Context(), opts)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newTaskListResponse(page))
+}
+
+// RestoreTaskHandler maneja la recuperación de una tarea de la papelera.
+// Si otra tarea del proyecto ya usa su nombre responde 409; el cuerpo opcional {"name": "..."}
+// permite restaurarla con otro nombre.
+// Método HTTP: POST
+// Ruta: /tasks/{id}/restore
+func (h *taskHandler) RestoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	var req restoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidPayload, "Invalid request payload: "+err.Error())
+		return
+	}
+
+	task, err := h.repo.WithCaller(requestCaller(r)).RestoreTask(r.Context(), id, req.Name)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", taskETag(task))
+	writeJSON(w, http.StatusOK, task)
+}
+
+// purgeTask elimina definitivamente la tarea (DELETE /tasks/{id}?hard=true).
+// If-Match se evalúa contra la tarea, esté o no en la papelera.
+func (h *taskHandler) purgeTask(w http.ResponseWriter, r *http.Request, id uint) {
+	version, ok := checkIfMatchWith(w, r, id, h.findTask)
+	if !ok {
+		return
+	}
+	if err := h.repo.WithCaller(requestCaller(r)).PurgeTask(r.Context(), id, version); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findTask busca la tarea entre las activas y, si no existe, en la papelera
+func (h *taskHandler) findTask(ctx context.Context, id uint) (*models.Task, error) {
+	task, err := h.repo.GetTaskByID(ctx, id)
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		return h.repo.GetDeletedTask(ctx, id)
+	}
+	return task, err
+}
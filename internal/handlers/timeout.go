@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// QueryTimeout middleware que limita el tiempo de las consultas de la solicitud
+// Los repositorios reciben r.Context(), por lo que al vencer el plazo (o al desconectarse el cliente)
+// la consulta en curso se cancela y el handler responde 504 (ver domainProblem).
+// Con d = 0 las consultas solo se cancelan si el cliente se desconecta.
+func QueryTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// WorkflowHandler define los handlers HTTP de los flujos de trabajo
+type WorkflowHandler interface {
+	GetWorkflowsHandler(w http.ResponseWriter, r *http.Request)
+	GetProjectWorkflowHandler(w http.ResponseWriter, r *http.Request)
+	SetProjectWorkflowHandler(w http.ResponseWriter, r *http.Request)
+	ResetProjectWorkflowHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// workflowHandler implementación de WorkflowHandler
+type workflowHandler struct {
+	repo repository.WorkflowRepository
+}
+
+// NewWorkflowHandler crea un nuevo WorkflowHandler con el repositorio proporcionado.
+func NewWorkflowHandler(repo repository.WorkflowRepository) WorkflowHandler {
+	return &workflowHandler{repo: repo}
+}
+
+// workflowListResponse cuerpo de respuesta de GET /workflows
+type workflowListResponse struct {
+	Items []models.Workflow `json:"items"`
+}
+
+// workflowRequest cuerpo de PUT /projects/{pid}/workflow
+// El orden de statuses es el orden de presentación; el primer estado es el inicial.
+type workflowRequest struct {
+	Name     string `json:"name"`
+	Statuses []struct {
+		Name     models.Status         `json:"name"`
+		Category models.StatusCategory `json:"category"`
+	} `json:"statuses"`
+	Transitions []struct {
+		From models.Status `json:"from"`
+		To   models.Status `json:"to"`
+	} `json:"transitions"`
+}
+
+// toWorkflow convierte la solicitud en el modelo Workflow
+func (req *workflowRequest) toWorkflow() *models.Workflow {
+	workflow := &models.Workflow{Name: req.Name}
+	for _, s := range req.Statuses {
+		workflow.Statuses = append(workflow.Statuses, models.WorkflowStatus{Name: s.Name, Category: s.Category})
+	}
+	for _, t := range req.Transitions {
+		workflow.Transitions = append(workflow.Transitions, models.WorkflowTransition{From: t.From, To: t.To})
+	}
+	return workflow
+}
+
+// GetWorkflowsHandler lista el flujo por defecto (project_id null) y los flujos propios de los proyectos.
+// La interfaz web lo usa para construir el selector de estado de cada tarea.
+// Método HTTP: GET
+// Ruta: /workflows
+func (h *workflowHandler) GetWorkflowsHandler(w http.ResponseWriter, r *http.Request) {
+	workflows, err := h.repo.GetWorkflows(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workflowListResponse{Items: workflows})
+}
+
+// GetProjectWorkflowHandler obtiene el flujo que aplica a las tareas del proyecto.
+// Método HTTP: GET
+// Ruta: /projects/{pid}/workflow
+func (h *workflowHandler) GetProjectWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	workflow, err := h.repo.GetProjectWorkflow(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workflow)
+}
+
+// SetProjectWorkflowHandler crea o reemplaza el flujo propio del proyecto.
+// Responde 409 si alguna tarea del proyecto usa un estado que no existe en el nuevo flujo.
+// Método HTTP: PUT
+// Ruta: /projects/{pid}/workflow
+func (h *workflowHandler) SetProjectWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	var req workflowRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	workflow := req.toWorkflow()
+	if err := h.repo.SetProjectWorkflow(r.Context(), id, workflow); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workflow)
+}
+
+// ResetProjectWorkflowHandler elimina el flujo propio del proyecto, que vuelve a usar el flujo por defecto.
+// Método HTTP: DELETE
+// Ruta: /projects/{pid}/workflow
+func (h *workflowHandler) ResetProjectWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.ResetProjectWorkflow(r.Context(), id); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Reminder datos de una notificación de tarea próxima a vencer
+type Reminder struct {
+	TaskID   uint      `json:"task_id"`
+	TaskName string    `json:"task_name"`
+	DueAt    time.Time `json:"due_at"`
+}
+
+// Notifier canal de entrega de recordatorios (log, webhook, ...)
+type Notifier interface {
+	Notify(ctx context.Context, reminder Reminder) error
+}
+
+// LogNotifier escribe los recordatorios en el log de la aplicación
+type LogNotifier struct{}
+
+// Notify registra el recordatorio con el tiempo restante hasta la fecha límite
+func (LogNotifier) Notify(_ context.Context, reminder Reminder) error {
+	log.Printf("Reminder: task %d %q is due at %s (in %s)",
+		reminder.TaskID, reminder.TaskName, reminder.DueAt.Format(time.RFC3339),
+		time.Until(reminder.DueAt).Round(time.Minute))
+	return nil
+}
+
+// WebhookNotifier envía los recordatorios como JSON mediante POST a una URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier crea un WebhookNotifier con un timeout de 10 segundos por envío
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// webhookPayload cuerpo enviado al webhook
+type webhookPayload struct {
+	Event    string   `json:"event"`
+	Reminder Reminder `json:"reminder"`
+}
+
+// Notify envía el recordatorio; cualquier respuesta distinta de 2xx se considera un error
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	body, err := json.Marshal(webhookPayload{Event: "task.due_soon", Reminder: reminder})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NewNotifier construye el notificador a partir de los nombres de canal configurados
+// Recibe: nombres ("log", "webhook") y la URL usada por el canal webhook
+// Retorna: MultiNotifier con los canales en el orden indicado, o error si algún nombre es desconocido
+func NewNotifier(names []string, webhookURL string) (Notifier, error) {
+	var notifiers MultiNotifier
+	for _, name := range names {
+		switch name {
+		case "log":
+			notifiers = append(notifiers, LogNotifier{})
+		case "webhook":
+			notifiers = append(notifiers, NewWebhookNotifier(webhookURL))
+		default:
+			return nil, fmt.Errorf("unknown reminder notifier %q", name)
+		}
+	}
+	return notifiers, nil
+}
+
+// MultiNotifier entrega el recordatorio a todos los notificadores configurados
+type MultiNotifier []Notifier
+
+// Notify invoca cada notificador y combina los errores que ocurran
+func (m MultiNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, reminder); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
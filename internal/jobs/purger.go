@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// PurgerActor autor con el que se registran en la auditoría las eliminaciones del purgador
+const PurgerActor = "system:trash-purger"
+
+// TrashPurger elimina periódicamente de forma definitiva las tareas que llevan en la papelera
+// más tiempo que el periodo de retención
+type TrashPurger struct {
+	repo      repository.TaskRepository
+	retention time.Duration // Tiempo que una tarea eliminada permanece recuperable
+	interval  time.Duration // Frecuencia de revisión
+}
+
+// NewTrashPurger crea el purgador de la papelera
+// Recibe: repositorio de tareas, periodo de retención y frecuencia de revisión
+func NewTrashPurger(repo repository.TaskRepository, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		repo:      repo.WithCaller(repository.Caller{Actor: PurgerActor}),
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run ejecuta purgas periódicas hasta que se cancele el contexto
+func (p *TrashPurger) Run(ctx context.Context) {
+	log.Printf("Trash purger started (retention=%s, interval=%s)", p.retention, p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			log.Println("Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge elimina las tareas que se movieron a la papelera antes de now-retention
+func (p *TrashPurger) purge(ctx context.Context, now time.Time) {
+	ids, err := p.repo.PurgeTrash(ctx, now.Add(-p.retention))
+	if len(ids) > 0 {
+		log.Printf("Trash purger: permanently deleted %d task(s)", len(ids))
+	}
+	if err != nil {
+		log.Printf("Trash purger: error purging tasks: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// ReminderScheduler revisa periódicamente las tareas próximas a vencer y envía un recordatorio
+// una sola vez por tarea y fecha límite. Si la fecha límite cambia, se vuelve a notificar.
+// El registro de recordatorios enviados vive en memoria: tras un reinicio pueden repetirse
+// los recordatorios de tareas que ya estaban dentro de la ventana de anticipación.
+type ReminderScheduler struct {
+	repo     repository.TaskRepository
+	notifier Notifier
+	lead     time.Duration // Anticipación con la que se notifica antes de la fecha límite
+	interval time.Duration // Frecuencia de revisión
+
+	mu   sync.Mutex
+	sent map[uint]time.Time // ID de tarea -> fecha límite ya notificada
+}
+
+// NewReminderScheduler crea el planificador de recordatorios
+// Recibe: repositorio de tareas, notificador, anticipación y frecuencia de revisión
+func NewReminderScheduler(repo repository.TaskRepository, notifier Notifier, lead, interval time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:     repo,
+		notifier: notifier,
+		lead:     lead,
+		interval: interval,
+		sent:     make(map[uint]time.Time),
+	}
+}
+
+// Run ejecuta revisiones periódicas hasta que se cancele el contexto
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	log.Printf("Reminder scheduler started (lead=%s, interval=%s)", s.lead, s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			log.Println("Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check busca las tareas abiertas que vencen dentro de la ventana [now, now+lead]
+// y notifica las que aún no tienen recordatorio para su fecha límite actual
+func (s *ReminderScheduler) check(ctx context.Context, now time.Time) {
+	until := now.Add(s.lead)
+	opts := repository.TaskQueryOptions{
+		DueAfter:  &now,
+		DueBefore: &until,
+		Open:      true,
+		Limit:     repository.MaxPageSize,
+	}
+
+	for {
+		page, err := s.repo.GetTasks(ctx, opts)
+		if err != nil {
+			log.Printf("Reminder scheduler: error retrieving tasks: %v", err)
+			return
+		}
+
+		for _, task := range page.Tasks {
+			if ctx.Err() != nil {
+				return
+			}
+			if !s.markSent(task.ID, *task.DueAt) {
+				continue
+			}
+			reminder := Reminder{TaskID: task.ID, TaskName: task.Name, DueAt: *task.DueAt}
+			if err := s.notifier.Notify(ctx, reminder); err != nil {
+				log.Printf("Reminder scheduler: error notifying task %d: %v", task.ID, err)
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	s.prune(now)
+}
+
+// markSent registra el recordatorio y retorna false si ya se había enviado para esa fecha límite
+func (s *ReminderScheduler) markSent(taskID uint, dueAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.sent[taskID]; ok && prev.Equal(dueAt) {
+		return false
+	}
+	s.sent[taskID] = dueAt
+	return true
+}
+
+// prune olvida los recordatorios de tareas cuya fecha límite ya pasó
+func (s *ReminderScheduler) prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, dueAt := range s.sent {
+		if dueAt.Before(now) {
+			delete(s.sent, id)
+		}
+	}
+}
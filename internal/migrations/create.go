@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// nameSeparators caracteres que se reemplazan por "_" en el nombre de una migración nueva
+var nameSeparators = regexp.MustCompile(`[\s\-]+`)
+
+// validName nombre de migración admitido en los archivos (ver fileName)
+var validName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create crea los archivos vacíos up y down de una migración nueva para cada dialecto,
+// con la versión siguiente a la mayor existente
+// Recibe: directorio de las migraciones en el código fuente (ej. internal/migrations) y nombre descriptivo
+// Retorna: las rutas de los archivos creados, o error si el nombre no es válido o no se pudieron escribir
+func Create(dir, name string) ([]string, error) {
+	name = nameSeparators.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "_")
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q: use letters, digits and underscores", name)
+	}
+
+	names := make([]string, 0, len(dialects))
+	for d := range dialects {
+		names = append(names, d)
+	}
+	sort.Strings(names)
+
+	var version uint64
+	for _, d := range names {
+		entries, err := os.ReadDir(filepath.Join(dir, d))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if m := fileName.FindStringSubmatch(entry.Name()); m != nil {
+				if v, _ := strconv.ParseUint(m[1], 10, 64); v > version {
+					version = v
+				}
+			}
+		}
+	}
+	version++
+
+	var paths []string
+	for _, d := range names {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, d, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %s (%s, %s)\n", name, d, direction)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,74 @@
+// Package migrations aplica el esquema de la base de datos con migraciones SQL numeradas.
+// Los archivos se incluyen en el binario (embed) en un directorio por base de datos (postgres, sqlite),
+// con el formato NNNN_nombre.up.sql / NNNN_nombre.down.sql.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Migration cambio del esquema con su versión y las sentencias para aplicarlo y revertirlo
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// fileName formato de los archivos de migración: versión, nombre y dirección
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// load lee las migraciones del dialecto ordenadas por versión
+// Retorna: error si un archivo no sigue el formato, una versión se repite o le falta su archivo up o down
+func load(fsys fs.FS, dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("migrations for %s: %w", dialect, err)
+	}
+	byVersion := map[uint64]*Migration{}
+	seen := map[uint64]map[string]bool{} // Direcciones (up, down) encontradas de cada versión
+	for _, entry := range entries {
+		m := fileName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %s/%s", dialect, entry.Name())
+		}
+		version, _ := strconv.ParseUint(m[1], 10, 64)
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+			seen[version] = map[string]bool{}
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("duplicate migration version %d in %s: %s and %s", version, dialect, migration.Name, m[2])
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		seen[version][m[3]] = true
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if !seen[migration.Version]["up"] || !seen[migration.Version]["down"] {
+			return nil, fmt.Errorf("migration %s/%04d_%s needs both up and down files", dialect, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
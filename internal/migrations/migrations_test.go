@@ -0,0 +1,285 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/config"
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// allModels modelos persistidos; el esquema de las migraciones debe tener todas sus columnas
+var allModels = []interface{}{&models.Project{}, &models.Task{}, &models.Tag{}, &models.TaskDependency{},
+	&models.Workflow{}, &models.WorkflowStatus{}, &models.WorkflowTransition{}, &models.TaskStatusEvent{}, &models.AuditEntry{}, &models.CalendarFeed{}}
+
+var ctx = context.Background()
+
+// openSQLite abre una base de datos SQLite vacía en un directorio temporal
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := config.Config{StorageDriver: config.StorageSQLite, SQLitePath: filepath.Join(t.TempDir(), "tasks.db")}
+	db, err := cfg.InitDb()
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	return db
+}
+
+func newMigrator(t *testing.T, db *gorm.DB) Migrator {
+	t.Helper()
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	return m
+}
+
+func TestEmbeddedMigrationsMatchAcrossDialects(t *testing.T) {
+	var versions [][]string
+	for d := range dialects {
+		migrations, err := load(files, d)
+		if err != nil {
+			t.Fatalf("load %s: %v", d, err)
+		}
+		if len(migrations) == 0 {
+			t.Fatalf("no migrations for %s", d)
+		}
+		var names []string
+		for _, m := range migrations {
+			names = append(names, m.Name)
+		}
+		versions = append(versions, names)
+	}
+	for _, names := range versions[1:] {
+		if !reflect.DeepEqual(names, versions[0]) {
+			t.Errorf("dialects have different migrations: %v vs %v", names, versions[0])
+		}
+	}
+}
+
+func TestUpCreatesModelSchema(t *testing.T) {
+	db := openSQLite(t)
+	m := newMigrator(t, db)
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) == 0 {
+		t.Fatal("Up applied no migrations")
+	}
+	for _, model := range allModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+				t.Errorf("table %s has no column %s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+	if !db.Migrator().HasTable("task_tags") {
+		t.Error("join table task_tags was not created")
+	}
+
+	if again, err := m.Up(ctx); err != nil || len(again) != 0 {
+		t.Errorf("second Up = %d migrations, %v; want none", len(again), err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil || s.Missing {
+			t.Errorf("status %+v, want applied", s)
+		}
+	}
+}
+
+func TestDownRevertsMigrations(t *testing.T) {
+	db := openSQLite(t)
+	m := newMigrator(t, db)
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	reverted, err := m.Down(ctx, len(applied))
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if len(reverted) != len(applied) || reverted[0].Version != applied[len(applied)-1].Version {
+		t.Errorf("Down reverted %v, want %v in reverse order", reverted, applied)
+	}
+	if db.Migrator().HasTable("tasks") {
+		t.Error("tasks table still exists after reverting every migration")
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			t.Errorf("status %+v, want pending", s)
+		}
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+}
+
+func TestUpAdoptsAutoMigratedDatabase(t *testing.T) {
+	db := openSQLite(t)
+	if err := db.AutoMigrate(allModels...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO tasks (name, description, status, priority, created_at) VALUES ('Legacy', '', 'Completed', 'low', CURRENT_TIMESTAMP)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newMigrator(t, db).Up(ctx); err != nil {
+		t.Fatalf("Up on an AutoMigrate schema: %v", err)
+	}
+	var task models.Task
+	if err := db.First(&task).Error; err != nil {
+		t.Fatal(err)
+	}
+	if task.StatusCategory != models.CategoryDone {
+		t.Errorf("status_category = %q, want backfilled %q", task.StatusCategory, models.CategoryDone)
+	}
+	var events int64
+	db.Model(&models.TaskStatusEvent{}).Where("task_id = ?", task.ID).Count(&events)
+	if events != 1 {
+		t.Errorf("status events = %d, want the backfilled creation event", events)
+	}
+}
+
+// baselineTask modelo de tarea de la primera versión de la aplicación, que solo usaba AutoMigrate
+type baselineTask struct {
+	gorm.Model
+	Name        string `gorm:"uniqueIndex;not null;size:100"`
+	Description string `gorm:"size:255;not null"`
+	Status      string `gorm:"type:varchar(20);default:'To do';not null"`
+}
+
+func (baselineTask) TableName() string { return "tasks" }
+
+func TestUpUpgradesBaselineDatabase(t *testing.T) {
+	db := openSQLite(t)
+	if err := db.AutoMigrate(&baselineTask{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&baselineTask{Name: "Legacy", Status: "In progress"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newMigrator(t, db).Up(ctx); err != nil {
+		t.Fatalf("Up on the baseline schema: %v", err)
+	}
+	var task models.Task
+	if err := db.First(&task).Error; err != nil {
+		t.Fatal(err)
+	}
+	if task.StatusCategory != models.CategoryActive || task.Priority != models.PriorityMedium || task.Version != 1 {
+		t.Errorf("task = %+v, want backfilled category and column defaults", task)
+	}
+	var events int64
+	db.Model(&models.TaskStatusEvent{}).Where("task_id = ?", task.ID).Count(&events)
+	if events != 1 {
+		t.Errorf("status events = %d, want the backfilled creation event", events)
+	}
+	// El nombre ya no es único globalmente, solo dentro del proyecto
+	if err := db.Exec("INSERT INTO tasks (name, description, project_id) VALUES ('Legacy', '', 1)").Error; err != nil {
+		t.Errorf("same name in another project: %v", err)
+	}
+}
+
+func TestStatusReportsMissingMigrations(t *testing.T) {
+	db := openSQLite(t)
+	m := newMigrator(t, db)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&schemaMigration{Version: 9999, Name: "from_newer_binary"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := statuses[len(statuses)-1]
+	if last.Version != 9999 || !last.Missing {
+		t.Errorf("last status = %+v, want missing version 9999", last)
+	}
+	if _, err := m.Down(ctx, 1); err == nil || !strings.Contains(err.Error(), "not included") {
+		t.Errorf("Down of a missing migration: err = %v", err)
+	}
+}
+
+func TestLoadValidatesFiles(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("SELECT 1;")}
+	tests := map[string]fstest.MapFS{
+		"invalid name":   {"sqlite/0001-initial.up.sql": file},
+		"missing down":   {"sqlite/0001_initial.up.sql": file},
+		"duplicate":      {"sqlite/0001_a.up.sql": file, "sqlite/0001_a.down.sql": file, "sqlite/0001_b.up.sql": file},
+		"missing folder": {"postgres/0001_initial.up.sql": file},
+	}
+	for name, fsys := range tests {
+		if _, err := load(fsys, "sqlite"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	migrations, err := load(fstest.MapFS{
+		"sqlite/0002_second.up.sql":   file,
+		"sqlite/0002_second.down.sql": &fstest.MapFile{}, // Reversión vacía
+		"sqlite/0001_first.up.sql":    file,
+		"sqlite/0001_first.down.sql":  file,
+	}, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "first" || migrations[1].Version != 2 {
+		t.Errorf("migrations = %+v", migrations)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for d := range dialects {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sqlite", "0007_existing.up.sql"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := Create(dir, "Add due-date Index")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(paths) != 2*len(dialects) {
+		t.Fatalf("created %v", paths)
+	}
+	for _, p := range paths {
+		if base := filepath.Base(p); !strings.HasPrefix(base, "0008_add_due_date_index.") {
+			t.Errorf("file %s, want version 0008 and a normalized name", base)
+		}
+		if _, err := os.Stat(p); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := Create(dir, "drop tasks; --"); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+}
@@ -0,0 +1,245 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migrator aplica y revierte las migraciones del dialecto de la base de datos,
+// registrando las aplicadas en la tabla schema_migrations
+type Migrator interface {
+	Up(ctx context.Context) ([]Migration, error)
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Status(ctx context.Context) ([]Status, error)
+}
+
+// Status estado de una migración en la base de datos
+type Status struct {
+	Version   uint64
+	Name      string
+	AppliedAt *time.Time // Fecha en que se aplicó (nil = pendiente)
+	Missing   bool       // Aplicada pero sin archivos en este binario (ej. la aplicó una versión más nueva)
+}
+
+// dialect sentencias propias de cada base de datos
+type dialect struct {
+	createTable string
+	lock        string // Bloqueo exclusivo de la conexión mientras se migra ("" = sin bloqueo)
+	unlock      string
+	// prepare adapta el SQL de una migración a la base de datos antes de ejecutarlo (nil = sin cambios)
+	prepare func(tx *gorm.DB, script string) (string, error)
+}
+
+// lockKey clave del bloqueo consultivo de PostgreSQL que serializa las migraciones entre réplicas
+const lockKey int64 = 0x7461736b6d6967 // "taskmig"
+
+// dialects dialectos admitidos, por nombre del dialecto de GORM
+// En SQLite no hay bloqueos consultivos: la base es un archivo local y cada migración se aplica en una
+// transacción, por lo que dos procesos no pueden registrar la misma versión (clave primaria).
+var dialects = map[string]dialect{
+	"postgres": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY, name varchar(255) NOT NULL, applied_at timestamptz NOT NULL)`,
+		lock:   "SELECT pg_advisory_lock(?)",
+		unlock: "SELECT pg_advisory_unlock(?)",
+	},
+	"sqlite": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer PRIMARY KEY, name text NOT NULL, applied_at datetime NOT NULL)`,
+		prepare: addColumnIfNotExists,
+	},
+}
+
+// addColumnStatement sentencia ALTER TABLE ... ADD COLUMN IF NOT EXISTS, escrita en una sola línea
+var addColumnStatement = regexp.MustCompile(`(?m)^ALTER TABLE "(\w+)" ADD COLUMN IF NOT EXISTS "(\w+)" (.*)$`)
+
+// addColumnIfNotExists emula en SQLite, que no lo admite, ADD COLUMN IF NOT EXISTS:
+// omite las sentencias de las columnas que ya existen y quita IF NOT EXISTS del resto
+func addColumnIfNotExists(tx *gorm.DB, script string) (string, error) {
+	var err error
+	script = addColumnStatement.ReplaceAllStringFunc(script, func(statement string) string {
+		m := addColumnStatement.FindStringSubmatch(statement)
+		var count int64
+		if e := tx.Raw("SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", m[1], m[2]).Scan(&count).Error; e != nil {
+			err = e
+		}
+		if count > 0 {
+			return "-- " + statement
+		}
+		return fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN "%s" %s`, m[1], m[2], m[3])
+	})
+	return script, err
+}
+
+// schemaMigration fila de la tabla schema_migrations
+type schemaMigration struct {
+	Version   uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName nombre de la tabla de migraciones aplicadas
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// migrator implementación de Migrator sobre GORM
+type migrator struct {
+	db         *gorm.DB
+	dialect    dialect
+	migrations []Migration // Ordenadas por versión
+}
+
+// NewMigrator crea el Migrator con las migraciones incluidas en el binario para el dialecto de la conexión
+// Retorna: error si la base de datos no es PostgreSQL ni SQLite, o si los archivos de migración no son válidos
+func NewMigrator(db *gorm.DB) (Migrator, error) {
+	name := db.Dialector.Name()
+	d, ok := dialects[name]
+	if !ok {
+		return nil, fmt.Errorf("migrations are not supported for database %q", name)
+	}
+	migrations, err := load(files, name)
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{db: db, dialect: d, migrations: migrations}, nil
+}
+
+// Up aplica en orden las migraciones pendientes, cada una en su propia transacción
+// Retorna: las migraciones aplicadas (hasta la que falló, si alguna falla)
+func (m *migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.exec(tx, migration.Up); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down revierte las últimas migraciones aplicadas, de la más reciente a la más antigua
+// Recibe: número de migraciones a revertir
+// Retorna: las migraciones revertidas, o error si alguna no tiene archivos en este binario o su reversión falla
+func (m *migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	byVersion := map[uint64]Migration{}
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		var rows []schemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			migration, ok := byVersion[row.Version]
+			if !ok {
+				return fmt.Errorf("migration %04d_%s is not included in this binary", row.Version, row.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.exec(tx, migration.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, row.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status obtiene el estado de las migraciones del binario y de las aplicadas que no incluye, ordenadas por versión
+func (m *migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if row, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &row.AppliedAt
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, row := range applied {
+			statuses = append(statuses, Status{Version: row.Version, Name: row.Name, AppliedAt: &row.AppliedAt, Missing: true})
+		}
+		return nil
+	})
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, err
+}
+
+// locked ejecuta fn sobre una sola conexión que tiene el bloqueo de migraciones, con la tabla schema_migrations creada
+// El bloqueo es de la conexión (no de una transacción) para que cada migración pueda tener su propia transacción.
+func (m *migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if m.dialect.lock != "" {
+			if err := conn.Exec(m.dialect.lock, lockKey).Error; err != nil {
+				return fmt.Errorf("acquiring migration lock: %w", err)
+			}
+			defer func() {
+				// Se libera aunque ctx se haya cancelado, para no devolver al pool una conexión con el bloqueo
+				if err := conn.WithContext(context.WithoutCancel(ctx)).Exec(m.dialect.unlock, lockKey).Error; err != nil {
+					log.Printf("Error releasing migration lock: %v", err)
+				}
+			}()
+		}
+		if err := conn.Exec(m.dialect.createTable).Error; err != nil {
+			return fmt.Errorf("creating schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+// exec ejecuta el SQL de una migración, adaptado a la base de datos (ver dialect.prepare)
+func (m *migrator) exec(tx *gorm.DB, script string) error {
+	if m.dialect.prepare != nil {
+		var err error
+		if script, err = m.dialect.prepare(tx, script); err != nil {
+			return err
+		}
+	}
+	return tx.Exec(script).Error
+}
+
+// applied obtiene las migraciones registradas en schema_migrations, por versión
+func (m *migrator) applied(conn *gorm.DB) (map[uint64]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := conn.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
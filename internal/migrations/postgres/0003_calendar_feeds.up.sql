@@ -0,0 +1,7 @@
+-- Versión del token de cada feed iCalendar, para poder renovarlo sin cambiar CALENDAR_SECRET
+CREATE TABLE IF NOT EXISTS "calendar_feeds" (
+    "scope" varchar(50) NOT NULL,
+    "version" bigint NOT NULL DEFAULT 1,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("scope")
+);
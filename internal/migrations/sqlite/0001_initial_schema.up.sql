@@ -0,0 +1,15 @@
+-- Esquema inicial: la tabla de tareas que creaba AutoMigrate antes de los proyectos, etiquetas y flujos de trabajo.
+-- Usa IF NOT EXISTS para adoptar las bases de datos creadas antes de las migraciones versionadas;
+-- las columnas y tablas posteriores se agregan en 0002_extended_schema.
+
+CREATE TABLE IF NOT EXISTS "tasks" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "name" text NOT NULL,
+    "description" text NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'To do'
+);
+CREATE INDEX IF NOT EXISTS "idx_tasks_deleted_at" ON "tasks" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tasks_name" ON "tasks" ("name");
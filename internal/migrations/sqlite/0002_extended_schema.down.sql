@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS "audit_entries";
+DROP TABLE IF EXISTS "task_status_events";
+DROP TABLE IF EXISTS "workflow_transitions";
+DROP TABLE IF EXISTS "workflow_statuses";
+DROP TABLE IF EXISTS "workflows";
+DROP TABLE IF EXISTS "task_dependencies";
+DROP TABLE IF EXISTS "task_tags";
+DROP TABLE IF EXISTS "tags";
+DROP TABLE IF EXISTS "projects";
+
+DROP INDEX IF EXISTS "idx_tasks_project_name";
+DROP INDEX IF EXISTS "idx_tasks_due_at";
+DROP INDEX IF EXISTS "idx_tasks_start_at";
+DROP INDEX IF EXISTS "idx_tasks_status_category";
+DROP INDEX IF EXISTS "idx_tasks_parent_id";
+DROP INDEX IF EXISTS "idx_tasks_project_id";
+ALTER TABLE "tasks" DROP COLUMN "due_at";
+ALTER TABLE "tasks" DROP COLUMN "start_at";
+ALTER TABLE "tasks" DROP COLUMN "version";
+ALTER TABLE "tasks" DROP COLUMN "priority";
+ALTER TABLE "tasks" DROP COLUMN "status_category";
+ALTER TABLE "tasks" DROP COLUMN "parent_id";
+ALTER TABLE "tasks" DROP COLUMN "project_id";
+-- Vuelve a ser único globalmente: falla si hay tareas con el mismo nombre en distintos proyectos
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tasks_name" ON "tasks" ("name");
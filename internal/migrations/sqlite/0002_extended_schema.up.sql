@@ -0,0 +1,122 @@
+-- Proyectos, subtareas, etiquetas, dependencias, flujos de trabajo, historial de estados y auditoría.
+-- Usa IF NOT EXISTS para adoptar tanto las bases de datos con el esquema inicial como las creadas
+-- con AutoMigrate por versiones intermedias, que ya tienen algunas de estas tablas y columnas.
+-- Sin índice de texto completo: en SQLite la búsqueda usa LIKE (ver searchTermsQuery).
+-- SQLite no admite ADD COLUMN IF NOT EXISTS: el migrador lo emula (ver addColumnIfNotExists).
+
+CREATE TABLE IF NOT EXISTS "projects" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "deleted_at" datetime,
+    "name" text NOT NULL,
+    "description" text NOT NULL,
+    "archived_at" datetime
+);
+CREATE INDEX IF NOT EXISTS "idx_projects_archived_at" ON "projects" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_projects_deleted_at" ON "projects" ("deleted_at");
+-- Nombre de proyecto único entre los proyectos no eliminados
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_projects_name" ON "projects" ("name") WHERE "deleted_at" IS NULL;
+
+-- Columnas de las tareas agregadas después del esquema inicial (IF NOT EXISTS: las bases de datos
+-- creadas con AutoMigrate por versiones intermedias ya pueden tenerlas)
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "project_id" integer;
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "parent_id" integer;
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "status_category" varchar(10);
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "priority" varchar(10) NOT NULL DEFAULT 'medium';
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "start_at" datetime;
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "due_at" datetime;
+CREATE INDEX IF NOT EXISTS "idx_tasks_project_id" ON "tasks" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_tasks_parent_id" ON "tasks" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_tasks_status_category" ON "tasks" ("status_category");
+CREATE INDEX IF NOT EXISTS "idx_tasks_start_at" ON "tasks" ("start_at");
+CREATE INDEX IF NOT EXISTS "idx_tasks_due_at" ON "tasks" ("due_at");
+-- El nombre de la tarea era único globalmente e incluía las filas eliminadas;
+-- ahora es único por proyecto entre las tareas no eliminadas
+DROP INDEX IF EXISTS "idx_tasks_name";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tasks_project_name" ON "tasks" (COALESCE("project_id", 0), "name") WHERE "deleted_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "tags" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "name" text NOT NULL,
+    "created_at" datetime,
+    "updated_at" datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_name" ON "tags" ("name");
+
+CREATE TABLE IF NOT EXISTS "task_tags" (
+    "task_id" integer,
+    "tag_id" integer,
+    PRIMARY KEY ("task_id", "tag_id"),
+    CONSTRAINT "fk_task_tags_task" FOREIGN KEY ("task_id") REFERENCES "tasks" ("id"),
+    CONSTRAINT "fk_task_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags" ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "task_dependencies" (
+    "blocker_id" integer,
+    "blocked_id" integer,
+    "created_at" datetime,
+    PRIMARY KEY ("blocker_id", "blocked_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_task_dependencies_blocked_id" ON "task_dependencies" ("blocked_id");
+
+CREATE TABLE IF NOT EXISTS "workflows" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "project_id" integer,
+    "name" text NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_workflows_project_id" ON "workflows" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "workflow_statuses" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "workflow_id" integer NOT NULL,
+    "name" varchar(20) NOT NULL,
+    "category" varchar(10) NOT NULL,
+    "position" integer NOT NULL,
+    CONSTRAINT "fk_workflows_statuses" FOREIGN KEY ("workflow_id") REFERENCES "workflows" ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_workflow_status_name" ON "workflow_statuses" ("workflow_id", "name");
+
+CREATE TABLE IF NOT EXISTS "workflow_transitions" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "workflow_id" integer NOT NULL,
+    "from" varchar(20) NOT NULL,
+    "to" varchar(20) NOT NULL,
+    CONSTRAINT "fk_workflows_transitions" FOREIGN KEY ("workflow_id") REFERENCES "workflows" ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_workflow_transitions_workflow_id" ON "workflow_transitions" ("workflow_id");
+
+CREATE TABLE IF NOT EXISTS "task_status_events" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "task_id" integer NOT NULL,
+    "from_status" varchar(20),
+    "to_status" varchar(20) NOT NULL,
+    "to_category" varchar(10) NOT NULL,
+    "actor" text,
+    "created_at" datetime
+);
+CREATE INDEX IF NOT EXISTS "idx_task_status_events_task_id" ON "task_status_events" ("task_id");
+CREATE INDEX IF NOT EXISTS "idx_task_status_events_created_at" ON "task_status_events" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "audit_entries" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "task_id" integer NOT NULL,
+    "action" varchar(10) NOT NULL,
+    "actor" text,
+    "request_id" text,
+    "changes" text NOT NULL,
+    "created_at" datetime
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_task_id" ON "audit_entries" ("task_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_actor" ON "audit_entries" ("actor");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_created_at" ON "audit_entries" ("created_at");
+
+-- Categoría de las tareas creadas antes de los flujos de trabajo (solo existían los estados del flujo por defecto)
+UPDATE "tasks" SET "status_category" = CASE "status" WHEN 'Completed' THEN 'done' WHEN 'In progress' THEN 'active' ELSE 'todo' END
+WHERE "status_category" IS NULL;
+
+-- Evento de creación de las tareas anteriores al historial de estados
+INSERT INTO "task_status_events" ("task_id", "to_status", "to_category", "created_at")
+SELECT "id", "status", "status_category", "created_at" FROM "tasks"
+WHERE NOT EXISTS (SELECT 1 FROM "task_status_events" WHERE "task_status_events"."task_id" = "tasks"."id");
@@ -0,0 +1,7 @@
+-- Versión del token de cada feed iCalendar, para poder renovarlo sin cambiar CALENDAR_SECRET
+CREATE TABLE IF NOT EXISTS "calendar_feeds" (
+    "scope" varchar(50) NOT NULL,
+    "version" integer NOT NULL DEFAULT 1,
+    "updated_at" datetime,
+    PRIMARY KEY ("scope")
+);
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditAction tipo de operación registrada en la auditoría
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"  // Creación de una tarea
+	AuditUpdate  AuditAction = "update"  // Actualización completa (PUT) o parcial (PATCH)
+	AuditDelete  AuditAction = "delete"  // Eliminación de una tarea (pasa a la papelera)
+	AuditRestore AuditAction = "restore" // Recuperación de una tarea de la papelera
+	AuditPurge   AuditAction = "purge"   // Eliminación definitiva de una tarea
+)
+
+// ErrAuditImmutable la auditoría es de solo inserción
+var ErrAuditImmutable = errors.New("audit entries cannot be modified or deleted")
+
+// FieldChange valor de un campo antes y después de una operación (nil si no existía)
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges diferencias por campo (nombre JSON del campo -> valores antes y después)
+// Implementa Scanner/Valuer para almacenarse como JSON en una columna de texto
+type AuditChanges map[string]FieldChange
+
+// Scan implementa la interfaz Scanner para leer el JSON almacenado
+func (c *AuditChanges) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for AuditChanges", value)
+	}
+	return json.Unmarshal(raw, c)
+}
+
+// Value implementa la interfaz Valuer para almacenar las diferencias como JSON
+func (c AuditChanges) Value() (driver.Value, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// AuditEntry registro inmutable de una modificación de tarea: quién, qué, cuándo y desde qué solicitud
+type AuditEntry struct {
+	ID        uint         `gorm:"primarykey" json:"id"`
+	TaskID    uint         `gorm:"not null;index" json:"task_id"`
+	Action    AuditAction  `gorm:"type:varchar(10);not null" json:"action"`
+	Actor     string       `gorm:"size:100;index" json:"actor"`       // Header X-Actor ("" si no se indicó)
+	RequestID string       `gorm:"size:100" json:"request_id"`        // ID de la solicitud HTTP
+	Changes   AuditChanges `gorm:"type:text;not null" json:"changes"` // Campos modificados con su valor anterior y nuevo
+	CreatedAt time.Time    `gorm:"index" json:"at"`
+}
+
+// BeforeUpdate impide modificar registros de auditoría
+func (*AuditEntry) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditImmutable
+}
+
+// BeforeDelete impide eliminar registros de auditoría
+func (*AuditEntry) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditImmutable
+}
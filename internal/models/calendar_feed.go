@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// CalendarFeed versión vigente del token de un feed iCalendar
+// Al renovar el token de un feed se incrementa su versión y las URLs anteriores dejan de ser válidas.
+// Un feed sin registro nunca se renovó y está en la versión 1.
+type CalendarFeed struct {
+	Scope     string    `gorm:"primaryKey;size:50" json:"scope"` // Alcance del feed ("all" o "project:{id}")
+	Version   uint      `gorm:"not null;default:1" json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
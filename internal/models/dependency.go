@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// TaskDependency relación de bloqueo entre tareas: BlockerID bloquea a BlockedID
+// (BlockedID no puede iniciarse, es decir pasar a un estado de categoría active, hasta que BlockerID esté completada).
+// Las dependencias forman un grafo dirigido acíclico.
+type TaskDependency struct {
+	BlockerID uint      `gorm:"primaryKey" json:"blocker_id"`       // Tarea que debe completarse primero
+	BlockedID uint      `gorm:"primaryKey;index" json:"blocked_id"` // Tarea que espera a BlockerID
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Project agrupa tareas (proyecto o tablero)
+// El nombre es único entre los proyectos no eliminados; las tareas de un proyecto archivado
+// se ocultan de los listados por defecto
+type Project struct {
+	gorm.Model
+	Name        string     `gorm:"not null;size:100" json:"name"`        // Nombre único entre proyectos activos
+	Description string     `gorm:"size:255;not null" json:"description"` // Descripción con máximo 255 caracteres
+	ArchivedAt  *time.Time `gorm:"index" json:"archived_at"`             // Fecha de archivado (nil si está activo)
+}
+
+// Archived indica si el proyecto está archivado
+func (p *Project) Archived() bool {
+	return p.ArchivedAt != nil
+}
+
+// Validate verifica las reglas de negocio del proyecto
+// Retorna: ValidationErrors con un elemento por cada campo inválido, o nil si es válido
+func (p *Project) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(p.Name) == "" {
+		errs.Add("name", "name is required")
+	} else if utf8.RuneCountInString(p.Name) > MaxNameLength {
+		errs.Add("name", fmt.Sprintf("name must be at most %d characters", MaxNameLength))
+	}
+	if utf8.RuneCountInString(p.Description) > MaxDescriptionLength {
+		errs.Add("description", fmt.Sprintf("description must be at most %d characters", MaxDescriptionLength))
+	}
+	return errs.Err()
+}
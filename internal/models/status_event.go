@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TaskStatusEvent registro de un cambio de estado de una tarea (incluida su creación)
+// Permite reconstruir cuánto tiempo pasó la tarea en cada estado.
+type TaskStatusEvent struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	TaskID     uint           `gorm:"not null;index" json:"task_id"`
+	FromStatus *Status        `gorm:"type:varchar(20)" json:"from"`              // Estado anterior (nil al crear la tarea)
+	ToStatus   Status         `gorm:"type:varchar(20);not null" json:"to"`       // Estado nuevo
+	ToCategory StatusCategory `gorm:"type:varchar(10);not null" json:"category"` // Categoría del estado nuevo en el flujo vigente
+	Actor      string         `gorm:"size:100" json:"actor,omitempty"`           // Quién hizo el cambio (header X-Actor), si se conoce
+	CreatedAt  time.Time      `gorm:"index" json:"at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTagNameLength longitud máxima del nombre de una etiqueta, debe coincidir con la etiqueta size de GORM
+const MaxTagNameLength = 50
+
+// Tag representa una etiqueta que clasifica tareas (ej. "backend", "bug", "customer-x")
+// La relación con Task es muchos a muchos a través de la tabla task_tags
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;not null;size:50" json:"name"` // Nombre único normalizado en minúsculas
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NormalizeTagName elimina espacios en los extremos y convierte el nombre a minúsculas,
+// de modo que "Backend" y "backend " identifiquen la misma etiqueta
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidateTagName verifica que el nombre (ya normalizado) sea usable como etiqueta y como segmento de URL
+// Retorna: ValidationErrors con el detalle del campo name, o nil si es válido
+func ValidateTagName(name string) error {
+	var errs ValidationErrors
+	switch {
+	case name == "":
+		errs.Add("name", "tag name is required")
+	case utf8.RuneCountInString(name) > MaxTagNameLength:
+		errs.Add("name", fmt.Sprintf("tag name must be at most %d characters", MaxTagNameLength))
+	case strings.IndexFunc(name, invalidTagRune) >= 0:
+		errs.Add("name", "tag name may only contain letters, digits, '-', '_', '.' and ':'")
+	}
+	return errs.Err()
+}
+
+// invalidTagRune indica si el carácter no está permitido en el nombre de una etiqueta
+func invalidTagRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return false
+	}
+	return !strings.ContainsRune("-_.:", r)
+}
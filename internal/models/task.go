@@ -3,10 +3,16 @@ package models
 import (
 	"database/sql/driver"
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"gorm.io/gorm"
 )
 
-// Status define los posibles estados de una tarea
+// Status define el estado de una tarea
+// Los estados permitidos dependen del flujo de trabajo del proyecto (ver Workflow);
+// las constantes corresponden a los estados del flujo por defecto.
 // Implementa Scanner/Valuer para integración con la base de datos
 type Status string
 
@@ -16,6 +22,9 @@ const (
 	Completed  Status = "Completed"   // Tarea finalizada exitosamente
 )
 
+// MaxStatusLength límite de longitud del nombre de un estado, debe coincidir con varchar(20)
+const MaxStatusLength = 20
+
 // Scan implementa la interfaz Scanner para convertir valores de la base de datos
 // Recibe: valor de la base de datos ([]byte o string)
 // Retorna: error si el tipo no es compatible o el estado es inválido
@@ -27,7 +36,7 @@ func (s *Status) Scan(value interface{}) error {
 	case string:
 		*s = Status(v)
 	default:
-		return fmt.Errorf("tipo %T no compatible para Status", value)
+		return fmt.Errorf("unsupported type %T for Status", value)
 	}
 	return s.IsValid()
 }
@@ -38,43 +47,148 @@ func (s Status) Value() (driver.Value, error) {
 	return string(s), nil
 }
 
-// IsValid verifica si el valor actual es un estado permitido
-// Retorna: error descriptivo si el estado no está en la lista blanca
-// Uso: Validación manual o en hooks de GORM
+// IsValid verifica el formato del estado (no vacío y con longitud permitida)
+// Retorna: error descriptivo si el formato no es válido
+// Nota: Que el estado exista en el flujo de trabajo del proyecto lo verifica el repositorio.
 func (s Status) IsValid() error {
-	switch s {
-	case ToDo, InProgress, Completed:
-		return nil
+	if strings.TrimSpace(string(s)) == "" {
+		return fmt.Errorf("status is required")
+	}
+	if utf8.RuneCountInString(string(s)) > MaxStatusLength {
+		return fmt.Errorf("status must be at most %d characters", MaxStatusLength)
+	}
+	return nil
+}
+
+// Priority define la prioridad de una tarea
+// Implementa Scanner/Valuer para integración con la base de datos
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"    // Puede esperar
+	PriorityMedium Priority = "medium" // Prioridad por defecto
+	PriorityHigh   Priority = "high"   // Debe atenderse pronto
+	PriorityUrgent Priority = "urgent" // Debe atenderse de inmediato
+)
+
+// priorities prioridades ordenadas de menor a mayor peso
+var priorities = []Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// Scan implementa la interfaz Scanner para convertir valores de la base de datos
+// Recibe: valor de la base de datos ([]byte o string)
+// Retorna: error si el tipo no es compatible o la prioridad es inválida
+func (p *Priority) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		*p = Priority(v)
+	case string:
+		*p = Priority(v)
 	default:
-		return fmt.Errorf("estado inválido: %s", s)
+		return fmt.Errorf("unsupported type %T for Priority", value)
 	}
+	return p.IsValid()
+}
+
+// Value implementa la interfaz Valuer para convertir valores a formato compatible con la base de datos
+// Retorna: representación string de la prioridad lista para almacenar
+func (p Priority) Value() (driver.Value, error) {
+	return string(p), nil
 }
 
-// ValidValues retorna los valores permitidos como strings
+// IsValid verifica si el valor actual es una prioridad permitida
+// Retorna: error descriptivo si la prioridad no está en la lista blanca
+func (p Priority) IsValid() error {
+	if p.Weight() == 0 {
+		return fmt.Errorf("invalid priority %q: must be one of %s", p, strings.Join(p.ValidValues(), ", "))
+	}
+	return nil
+}
+
+// ValidValues retorna los valores permitidos como strings, de menor a mayor prioridad
 // Propósito: Validación en capas superiores (ej: API, formularios)
-func (Status) ValidValues() []string {
-	return []string{
-		string(ToDo),
-		string(InProgress),
-		string(Completed),
+func (Priority) ValidValues() []string {
+	values := make([]string, len(priorities))
+	for i, p := range priorities {
+		values[i] = string(p)
+	}
+	return values
+}
+
+// Weight retorna el peso de la prioridad para ordenar (1 = low ... 4 = urgent, 0 si es inválida)
+func (p Priority) Weight() int {
+	for i, candidate := range priorities {
+		if p == candidate {
+			return i + 1
+		}
 	}
+	return 0
 }
 
 // Task representa una entidad de tarea en el sistema
 // Campos GORM: ID, CreatedAt, UpdatedAt, DeletedAt (embedded)
 type Task struct {
 	gorm.Model
-	Name        string `gorm:"uniqueIndex;not null;size:100" json:"name"` // Nombre único con máximo 100 caracteres
-	Description string `gorm:"size:255;not null" json:"description"`             // Descripción con máximo 255 caracteres
-	Status      Status `gorm:"type:varchar(20);default:'To do';not null" json:"status"` // Estado con valor por defecto
+	ProjectID      *uint          `gorm:"index" json:"project_id"`                                    // Proyecto al que pertenece (nil = sin proyecto)
+	ParentID       *uint          `gorm:"index" json:"parent_id"`                                     // Tarea padre (nil = tarea de primer nivel)
+	Name           string         `gorm:"not null;size:100" json:"name"`                              // Nombre único dentro del proyecto, máximo 100 caracteres
+	Description    string         `gorm:"size:255;not null" json:"description"`                       // Descripción con máximo 255 caracteres
+	Status         Status         `gorm:"type:varchar(20);default:'To do';not null" json:"status"`    // Estado con valor por defecto
+	StatusCategory StatusCategory `gorm:"type:varchar(10);index" json:"status_category"`              // Categoría del estado en el flujo del proyecto (la asigna el repositorio)
+	Priority       Priority       `gorm:"type:varchar(10);default:'medium';not null" json:"priority"` // Prioridad con valor por defecto
+	Version        uint           `gorm:"not null;default:1" json:"version"`                          // Versión para control de concurrencia optimista
+	StartAt        *time.Time     `gorm:"index" json:"start_at"`                                      // Fecha opcional de inicio planificado
+	DueAt          *time.Time     `gorm:"index" json:"due_at"`                                        // Fecha límite opcional
+	Tags           []Tag          `gorm:"many2many:task_tags;" json:"tags"`                           // Etiquetas (se gestionan con /tasks/{id}/tags)
+	Blocked        bool           `gorm:"-" json:"blocked"`                                           // Calculado: alguna tarea que la bloquea no está completada
+}
+
+// Límites de longitud de los campos, deben coincidir con las etiquetas size de GORM
+const (
+	MaxNameLength        = 100
+	MaxDescriptionLength = 255
+)
+
+// Validate verifica las reglas de negocio de la tarea
+// Retorna: ValidationErrors con un elemento por cada campo inválido, o nil si es válida
+func (t *Task) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(t.Name) == "" {
+		errs.Add("name", "name is required")
+	} else if utf8.RuneCountInString(t.Name) > MaxNameLength {
+		errs.Add("name", fmt.Sprintf("name must be at most %d characters", MaxNameLength))
+	}
+	if utf8.RuneCountInString(t.Description) > MaxDescriptionLength {
+		errs.Add("description", fmt.Sprintf("description must be at most %d characters", MaxDescriptionLength))
+	}
+	// Sin estado, el repositorio asigna el estado inicial del flujo de trabajo
+	if t.Status != "" {
+		if err := t.Status.IsValid(); err != nil {
+			errs.Add("status", err.Error())
+		}
+	}
+	if err := t.Priority.IsValid(); err != nil {
+		errs.Add("priority", err.Error())
+	}
+	if t.StartAt != nil && t.DueAt != nil && !t.StartAt.Before(*t.DueAt) {
+		errs.Add("start_at", "start_at must be before due_at")
+	}
+	return errs.Err()
 }
 
 // BeforeSave hook de ciclo de vida de GORM para validación automática
 // Se ejecuta antes de cualquier operación Create/Update
-// Retorna: error si la validación falla, abortando la operación
+// Retorna: ValidationErrors si la validación falla, abortando la operación
 func (t *Task) BeforeSave(tx *gorm.DB) error {
 	if err := t.Status.IsValid(); err != nil {
-		return fmt.Errorf("validación fallida al guardar tarea: %w", err)
+		return ValidationErrors{{Field: "status", Message: err.Error()}}
+	}
+	// La categoría la asigna el repositorio al resolver el estado en el flujo de trabajo;
+	// si falta, la escritura no pasó por esa validación
+	if err := t.StatusCategory.IsValid(); err != nil {
+		return ValidationErrors{{Field: "status_category", Message: err.Error()}}
+	}
+	if err := t.Priority.IsValid(); err != nil {
+		return ValidationErrors{{Field: "priority", Message: err.Error()}}
 	}
 	return nil
-}
\ No newline at end of file
+}
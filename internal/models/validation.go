@@ -0,0 +1,35 @@
+package models
+
+import "strings"
+
+// FieldError describe un error de validación sobre un campo específico
+type FieldError struct {
+	Field   string `json:"field"`   // Nombre del campo en JSON (ej. "name")
+	Message string `json:"message"` // Descripción legible del problema
+}
+
+// ValidationErrors agrupa los errores de validación de una entidad
+// Implementa error para poder retornarse y detectarse con errors.As
+type ValidationErrors []FieldError
+
+// Error une los mensajes de cada campo en un solo texto
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, fe := range v {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Add agrega un error de campo a la lista
+func (v *ValidationErrors) Add(field, message string) {
+	*v = append(*v, FieldError{Field: field, Message: message})
+}
+
+// Err retorna nil si no hay errores, para usar como valor de retorno de Validate
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// StatusCategory agrupa los estados de un flujo de trabajo según su significado,
+// independientemente del nombre que tengan en cada proyecto
+type StatusCategory string
+
+const (
+	CategoryTodo   StatusCategory = "todo"   // Trabajo aún no iniciado
+	CategoryActive StatusCategory = "active" // Trabajo en curso
+	CategoryDone   StatusCategory = "done"   // Trabajo terminado (cuenta como completado)
+)
+
+// IsValid verifica si la categoría es una de las permitidas
+func (c StatusCategory) IsValid() error {
+	switch c {
+	case CategoryTodo, CategoryActive, CategoryDone:
+		return nil
+	default:
+		return fmt.Errorf("invalid category %q: must be one of %s, %s, %s", c, CategoryTodo, CategoryActive, CategoryDone)
+	}
+}
+
+// Workflow flujo de trabajo de un proyecto: los estados que pueden tener sus tareas
+// y las transiciones permitidas entre ellos.
+// Las tareas sin proyecto, o de proyectos sin flujo propio, usan DefaultWorkflow.
+type Workflow struct {
+	ID          uint                 `gorm:"primarykey" json:"-"`
+	ProjectID   *uint                `gorm:"uniqueIndex" json:"project_id"` // nil = flujo por defecto
+	Name        string               `gorm:"size:100;not null" json:"name"`
+	Statuses    []WorkflowStatus     `json:"statuses"`    // Ordenados por Position; el primero es el estado inicial
+	Transitions []WorkflowTransition `json:"transitions"` // Cambios de estado permitidos
+}
+
+// WorkflowStatus estado de un flujo de trabajo
+type WorkflowStatus struct {
+	ID         uint           `gorm:"primarykey" json:"-"`
+	WorkflowID uint           `gorm:"not null;uniqueIndex:idx_workflow_status_name" json:"-"`
+	Name       Status         `gorm:"type:varchar(20);not null;uniqueIndex:idx_workflow_status_name" json:"name"`
+	Category   StatusCategory `gorm:"type:varchar(10);not null" json:"category"`
+	Position   int            `gorm:"not null" json:"-"`
+}
+
+// WorkflowTransition cambio de estado permitido dentro de un flujo de trabajo
+type WorkflowTransition struct {
+	ID         uint   `gorm:"primarykey" json:"-"`
+	WorkflowID uint   `gorm:"not null;index" json:"-"`
+	From       Status `gorm:"type:varchar(20);not null" json:"from"`
+	To         Status `gorm:"type:varchar(20);not null" json:"to"`
+}
+
+// DefaultWorkflow flujo por defecto: los estados históricos To do, In progress y Completed,
+// con cualquier cambio de estado permitido
+func DefaultWorkflow() *Workflow {
+	wf := &Workflow{
+		Name: "Default",
+		Statuses: []WorkflowStatus{
+			{Name: ToDo, Category: CategoryTodo, Position: 0},
+			{Name: InProgress, Category: CategoryActive, Position: 1},
+			{Name: Completed, Category: CategoryDone, Position: 2},
+		},
+	}
+	for _, from := range wf.Statuses {
+		for _, to := range wf.Statuses {
+			if from.Name != to.Name {
+				wf.Transitions = append(wf.Transitions, WorkflowTransition{From: from.Name, To: to.Name})
+			}
+		}
+	}
+	return wf
+}
+
+// MaxWorkflowNameLength límite de longitud del nombre, debe coincidir con la etiqueta size de GORM
+const MaxWorkflowNameLength = 100
+
+// Validate verifica las reglas del flujo de trabajo:
+// nombre obligatorio, al menos un estado de categoría done, nombres de estado únicos
+// y transiciones entre estados existentes
+// Retorna: ValidationErrors con un elemento por cada campo inválido, o nil si es válido
+func (w *Workflow) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(w.Name) == "" {
+		errs.Add("name", "name is required")
+	} else if utf8.RuneCountInString(w.Name) > MaxWorkflowNameLength {
+		errs.Add("name", fmt.Sprintf("name must be at most %d characters", MaxWorkflowNameLength))
+	}
+
+	if len(w.Statuses) == 0 {
+		errs.Add("statuses", "at least one status is required")
+	}
+	seen := make(map[Status]bool, len(w.Statuses))
+	hasDone := false
+	for i, s := range w.Statuses {
+		field := fmt.Sprintf("statuses[%d]", i)
+		if err := s.Name.IsValid(); err != nil {
+			errs.Add(field+".name", err.Error())
+		} else if seen[s.Name] {
+			errs.Add(field+".name", fmt.Sprintf("duplicate status %q", s.Name))
+		}
+		seen[s.Name] = true
+		if err := s.Category.IsValid(); err != nil {
+			errs.Add(field+".category", err.Error())
+		}
+		hasDone = hasDone || s.Category == CategoryDone
+	}
+	if len(w.Statuses) > 0 && !hasDone {
+		errs.Add("statuses", "at least one status must have the done category")
+	}
+
+	for i, t := range w.Transitions {
+		field := fmt.Sprintf("transitions[%d]", i)
+		if !seen[t.From] {
+			errs.Add(field+".from", fmt.Sprintf("unknown status %q", t.From))
+		}
+		if !seen[t.To] {
+			errs.Add(field+".to", fmt.Sprintf("unknown status %q", t.To))
+		}
+		if t.From == t.To {
+			errs.Add(field, "a transition must change the status")
+		}
+	}
+	return errs.Err()
+}
+
+// Status busca un estado por nombre
+func (w *Workflow) Status(name Status) (*WorkflowStatus, bool) {
+	for i := range w.Statuses {
+		if w.Statuses[i].Name == name {
+			return &w.Statuses[i], true
+		}
+	}
+	return nil, false
+}
+
+// Initial estado que reciben las tareas nuevas que no indican estado
+func (w *Workflow) Initial() Status {
+	return w.Statuses[0].Name
+}
+
+// FirstOfCategory primer estado del flujo con la categoría indicada
+func (w *Workflow) FirstOfCategory(category StatusCategory) (Status, bool) {
+	for _, s := range w.Statuses {
+		if s.Category == category {
+			return s.Name, true
+		}
+	}
+	return "", false
+}
+
+// CanTransition indica si una tarea puede pasar del estado from al estado to
+// Mantener el mismo estado siempre está permitido.
+func (w *Workflow) CanTransition(from, to Status) bool {
+	if from == to {
+		return true
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusNames retorna los nombres de los estados en orden
+func (w *Workflow) StatusNames() []string {
+	names := make([]string, len(w.Statuses))
+	for i, s := range w.Statuses {
+		names[i] = string(s.Name)
+	}
+	return names
+}
@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditQuery filtros para consultar la auditoría
+type AuditQuery struct {
+	TaskID *uint      // Solo registros de la tarea
+	Actor  string     // Solo registros del autor ("" = todos)
+	Since  *time.Time // Solo registros posteriores a esta fecha
+	Limit  int        // Máximo de registros (0 = DefaultPageSize); se ignora al exportar
+	Offset int        // Desplazamiento para paginación; se ignora al exportar
+}
+
+// AuditPage página de registros de auditoría con la paginación aplicada
+type AuditPage struct {
+	Entries []models.AuditEntry
+	Limit   int
+	Offset  int
+}
+
+// auditExportBatchSize registros leídos por consulta al exportar la auditoría completa
+const auditExportBatchSize = 500
+
+// AuditRepository define el acceso a la auditoría de tareas, que es de solo inserción
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry *models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, query AuditQuery) (*AuditPage, error)
+	ExportAuditEntries(ctx context.Context, query AuditQuery, fn func(*models.AuditEntry) error) error
+}
+
+// auditRepository implementación concreta de AuditRepository usando GORM
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository factory para crear instancias del repositorio de auditoría
+// Recibe: conexión a la base de datos (*gorm.DB)
+// Retorna: implementación de AuditRepository lista para usar
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// RecordAudit agrega un registro a la auditoría
+func (r *auditRepository) RecordAudit(ctx context.Context, entry *models.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// applyFilters agrega las condiciones WHERE de la consulta
+func (q *AuditQuery) applyFilters(db *gorm.DB) *gorm.DB {
+	if q.TaskID != nil {
+		db = db.Where("task_id = ?", *q.TaskID)
+	}
+	if q.Actor != "" {
+		db = db.Where("actor = ?", q.Actor)
+	}
+	if q.Since != nil {
+		db = db.Where("created_at >= ?", *q.Since)
+	}
+	return db
+}
+
+// GetAuditEntries obtiene una página de registros en orden cronológico
+// Retorna: ErrInvalidQuery si la paginación no es válida, o error de GORM
+func (r *auditRepository) GetAuditEntries(ctx context.Context, query AuditQuery) (*AuditPage, error) {
+	if err := normalizePage(&query.Limit, query.Offset); err != nil {
+		return nil, err
+	}
+	page := &AuditPage{Entries: []models.AuditEntry{}, Limit: query.Limit, Offset: query.Offset}
+	err := query.applyFilters(r.db.WithContext(ctx)).Order("id").Limit(query.Limit).Offset(query.Offset).Find(&page.Entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ExportAuditEntries recorre todos los registros que cumplen los filtros en orden cronológico,
+// leyéndolos por lotes para no cargar la auditoría completa en memoria
+// Recibe: filtros y función invocada por cada registro (si retorna error se detiene el recorrido)
+func (r *auditRepository) ExportAuditEntries(ctx context.Context, query AuditQuery, fn func(*models.AuditEntry) error) error {
+	var lastID uint
+	for {
+		var batch []models.AuditEntry
+		err := query.applyFilters(r.db.WithContext(ctx)).Where("id > ?", lastID).Order("id").Limit(auditExportBatchSize).Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		if len(batch) < auditExportBatchSize {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// auditedTaskRepository repositorio de tareas que registra en la auditoría cada creación, actualización
+// y eliminación de tareas, en la misma transacción que la escritura; las lecturas no cambian
+type auditedTaskRepository struct {
+	*repository
+}
+
+// NewAuditedTaskRepository crea un repositorio de tareas que audita sus escrituras
+// Recibe: conexión a la base de datos (*gorm.DB) y reglas opcionales (ver NewTaskRepository)
+// Retorna: TaskRepository que registra quién cambió qué (ver WithCaller)
+// Nota: El registro se escribe en la transacción de la operación; si no se puede guardar, la operación se revierte.
+func NewAuditedTaskRepository(db *gorm.DB, rules TaskRules) TaskRepository {
+	return &auditedTaskRepository{repository: &repository{db: db, rules: rules}}
+}
+
+// WithCaller retorna una copia del repositorio que atribuye los cambios a caller
+func (r *auditedTaskRepository) WithCaller(caller Caller) TaskRepository {
+	scoped := *r.repository
+	scoped.caller = caller
+	return &auditedTaskRepository{repository: &scoped}
+}
+
+// transaction ejecuta fn con el repositorio de tareas ligado a una transacción
+func (r *auditedTaskRepository) transaction(ctx context.Context, fn func(tasks *repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(r.withDB(tx))
+	})
+}
+
+// lockTask bloquea la fila de la tarea, esté o no en la papelera, hasta que termina la transacción
+// y retorna su estado actual, para auditar la diferencia con lo que escribe la operación
+// Retorna: ErrTaskNotFound o error de GORM
+func lockTask(ctx context.Context, tasks *repository, id uint) (*models.Task, error) {
+	var row models.Task
+	if err := forUpdate(tasks.db.WithContext(ctx).Unscoped()).Select("id", "deleted_at").First(&row, id).Error; err != nil {
+		return nil, fmt.Errorf("task with ID %d: %w", id, translateError(err))
+	}
+	if row.DeletedAt.Valid {
+		return tasks.GetDeletedTask(ctx, id)
+	}
+	return tasks.GetTaskByID(ctx, id)
+}
+
+// CreateTask crea la tarea y audita todos sus campos
+func (r *auditedTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	return r.transaction(ctx, func(tasks *repository) error {
+		if err := tasks.CreateTask(ctx, task); err != nil {
+			return err
+		}
+		return r.record(ctx, tasks.db, models.AuditCreate, task.ID, nil, task)
+	})
+}
+
+// UpdateTask actualiza la tarea y audita los campos que cambiaron
+func (r *auditedTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	return r.update(ctx, task, func(tasks *repository) error { return tasks.UpdateTask(ctx, task) })
+}
+
+// PatchTask actualiza parcialmente la tarea y audita los campos que cambiaron
+func (r *auditedTaskRepository) PatchTask(ctx context.Context, task *models.Task, columns []string) error {
+	return r.update(ctx, task, func(tasks *repository) error { return tasks.PatchTask(ctx, task, columns) })
+}
+
+// update captura la tarea antes y después de la escritura para auditar la diferencia
+func (r *auditedTaskRepository) update(ctx context.Context, task *models.Task, write func(tasks *repository) error) error {
+	return r.transaction(ctx, func(tasks *repository) error {
+		before, err := lockTask(ctx, tasks, task.ID)
+		if err != nil {
+			return err
+		}
+		if err := write(tasks); err != nil {
+			return err
+		}
+		return r.record(ctx, tasks.db, models.AuditUpdate, task.ID, before, task)
+	})
+}
+
+// DeleteTask elimina la tarea y audita el estado que tenía
+func (r *auditedTaskRepository) DeleteTask(ctx context.Context, id uint, expectedVersion uint) error {
+	return r.transaction(ctx, func(tasks *repository) error {
+		before, err := lockTask(ctx, tasks, id)
+		if err != nil {
+			return err
+		}
+		if err := tasks.DeleteTask(ctx, id, expectedVersion); err != nil {
+			return err
+		}
+		return r.record(ctx, tasks.db, models.AuditDelete, id, before, nil)
+	})
+}
+
+// RestoreTask recupera la tarea de la papelera y audita los campos que cambiaron al restaurarla
+func (r *auditedTaskRepository) RestoreTask(ctx context.Context, id uint, name string) (*models.Task, error) {
+	var task *models.Task
+	err := r.transaction(ctx, func(tasks *repository) error {
+		before, err := lockTask(ctx, tasks, id)
+		if err != nil {
+			return err
+		}
+		if task, err = tasks.RestoreTask(ctx, id, name); err != nil {
+			return err
+		}
+		return r.record(ctx, tasks.db, models.AuditRestore, id, before, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// PurgeTask elimina definitivamente la tarea y audita el estado que tenía
+func (r *auditedTaskRepository) PurgeTask(ctx context.Context, id uint, expectedVersion uint) error {
+	return r.transaction(ctx, func(tasks *repository) error {
+		before, err := lockTask(ctx, tasks, id)
+		if err != nil {
+			return err
+		}
+		if err := tasks.PurgeTask(ctx, id, expectedVersion); err != nil {
+			return err
+		}
+		return r.record(ctx, tasks.db, models.AuditPurge, id, before, nil)
+	})
+}
+
+// PurgeTrash vacía la papelera y audita cada tarea eliminada (sin detalle de campos) en la transacción de su lote
+func (r *auditedTaskRepository) PurgeTrash(ctx context.Context, before time.Time) ([]uint, error) {
+	return r.purgeTrash(ctx, before, func(tx *gorm.DB, ids []uint) error {
+		for _, id := range ids {
+			if err := r.record(ctx, tx, models.AuditPurge, id, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BulkTasks ejecuta el lote y audita cada operación aplicada en su propio savepoint, por lo que una operación
+// revertida (o un lote de prueba) no deja registros
+// El estado previo se captura dentro del lote, justo antes de aplicar cada operación.
+func (r *auditedTaskRepository) BulkTasks(ctx context.Context, ops []BulkOperation, opts BulkOptions) ([]BulkResult, error) {
+	return r.bulkTasks(ctx, ops, opts, func(tasks *repository, op BulkOperation) (BulkAction, *models.Task, error) {
+		var before *models.Task
+		switch op.Action {
+		case BulkUpdate, BulkUpsert:
+			if op.Action == BulkUpdate {
+				if _, err := lockTask(ctx, tasks, op.ID); err != nil {
+					return op.Action, nil, err
+				}
+			}
+			if apply := op.Apply; apply != nil {
+				op.Apply = func(task *models.Task) ([]string, error) {
+					snapshot := *task
+					before = &snapshot
+					return apply(task)
+				}
+			}
+		case BulkDelete:
+			task, err := lockTask(ctx, tasks, op.ID)
+			if err != nil {
+				return op.Action, nil, err
+			}
+			before = task
+		}
+
+		action, task, err := applyBulk(ctx, tasks, op)
+		if err != nil {
+			return action, task, err
+		}
+		switch action {
+		case BulkCreate:
+			err = r.record(ctx, tasks.db, models.AuditCreate, task.ID, nil, task)
+		case BulkUpdate:
+			err = r.record(ctx, tasks.db, models.AuditUpdate, task.ID, before, task)
+		case BulkDelete:
+			err = r.record(ctx, tasks.db, models.AuditDelete, op.ID, before, nil)
+		}
+		return action, task, err
+	})
+}
+
+// record guarda el registro de auditoría en la transacción indicada, que es la de la operación auditada
+// Las actualizaciones que no cambiaron ningún campo auditado no se registran.
+func (r *auditedTaskRepository) record(ctx context.Context, tx *gorm.DB, action models.AuditAction, taskID uint, before, after *models.Task) error {
+	changes, err := diffTasks(before, after)
+	if err != nil {
+		return fmt.Errorf("audit %s task %d: %w", action, taskID, err)
+	}
+	if action == models.AuditUpdate && len(changes) == 0 {
+		return nil
+	}
+	return NewAuditRepository(tx).RecordAudit(ctx, &models.AuditEntry{
+		TaskID:    taskID,
+		Action:    action,
+		Actor:     r.caller.Actor,
+		RequestID: r.caller.RequestID,
+		Changes:   changes,
+	})
+}
+
+// auditIgnoredFields campos redundantes, que cambian en toda escritura o calculados, por lo que no se auditan
+var auditIgnoredFields = map[string]bool{"ID": true, "UpdatedAt": true, "DeletedAt": true, "version": true, "blocked": true}
+
+// diffTasks compara la representación JSON de la tarea antes y después (nil si no existía o ya no existe)
+// Retorna: los campos cuyo valor cambió (un campo ausente equivale a null)
+func diffTasks(before, after *models.Task) (models.AuditChanges, error) {
+	beforeFields, err := taskFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := taskFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := models.AuditChanges{}
+	for _, fields := range []map[string]interface{}{beforeFields, afterFields} {
+		for field := range fields {
+			old, value := beforeFields[field], afterFields[field]
+			if !auditIgnoredFields[field] && !reflect.DeepEqual(old, value) {
+				changes[field] = models.FieldChange{Before: old, After: value}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// taskFields convierte la tarea en un mapa con los campos de su representación JSON
+func taskFields(task *models.Task) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if task == nil {
+		return fields, nil
+	}
+	raw, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, &fields)
+	return fields, err
+}
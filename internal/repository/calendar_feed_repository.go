@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarFeedRepository define el acceso a las versiones de los tokens de los feeds iCalendar
+type CalendarFeedRepository interface {
+	GetFeedVersions(ctx context.Context) (map[string]uint, error)
+	GetFeedVersion(ctx context.Context, scope string) (uint, error)
+	RotateFeed(ctx context.Context, scope string) (uint, error)
+}
+
+// calendarFeedRepository implementación concreta de CalendarFeedRepository usando GORM
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository factory para crear instancias del repositorio de feeds iCalendar
+// Recibe: conexión a la base de datos (*gorm.DB)
+// Retorna: implementación de CalendarFeedRepository lista para usar
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// GetFeedVersions obtiene la versión de los feeds que se renovaron alguna vez, por alcance
+// Los feeds que no aparecen están en la versión 1.
+func (r *calendarFeedRepository) GetFeedVersions(ctx context.Context) (map[string]uint, error) {
+	var feeds []models.CalendarFeed
+	if err := r.db.WithContext(ctx).Find(&feeds).Error; err != nil {
+		return nil, err
+	}
+	versions := make(map[string]uint, len(feeds))
+	for _, feed := range feeds {
+		versions[feed.Scope] = feed.Version
+	}
+	return versions, nil
+}
+
+// GetFeedVersion obtiene la versión vigente de un feed (1 si nunca se renovó)
+func (r *calendarFeedRepository) GetFeedVersion(ctx context.Context, scope string) (uint, error) {
+	var versions []uint
+	err := r.db.WithContext(ctx).Model(&models.CalendarFeed{}).Where("scope = ?", scope).Pluck("version", &versions).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 1, nil
+	}
+	return versions[0], nil
+}
+
+// RotateFeed incrementa la versión del token del feed, invalidando sus URLs anteriores
+// Retorna: la nueva versión, o error de GORM
+func (r *calendarFeedRepository) RotateFeed(ctx context.Context, scope string) (uint, error) {
+	feed := models.CalendarFeed{Scope: scope, Version: 2} // Primera renovación de un feed sin registro
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"version":    gorm.Expr(`"calendar_feeds"."version" + 1`),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}),
+		}).Create(&feed).Error
+		if err != nil {
+			return err
+		}
+		return tx.First(&feed, "scope = ?", scope).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return feed.Version, nil
+}
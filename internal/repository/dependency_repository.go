@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errores centinela de las dependencias entre tareas
+var (
+	ErrDependencyCycle = errors.New("a task cannot depend on itself or on a task it blocks") // La nueva dependencia generaría un ciclo
+	ErrTaskBlocked     = errors.New("task is blocked by tasks that are not completed")       // No puede iniciarse con bloqueadores abiertos
+)
+
+// downstreamQuery CTE recursiva con los IDs de todas las tareas bloqueadas directa o indirectamente
+// por la tarea indicada. Incluye las aristas de tareas eliminadas para que restaurarlas no genere ciclos.
+const downstreamQuery = `
+	WITH RECURSIVE downstream(id) AS (
+		SELECT blocked_id FROM task_dependencies WHERE blocker_id = ?
+		UNION
+		SELECT task_dependencies.blocked_id FROM task_dependencies
+		JOIN downstream ON task_dependencies.blocker_id = downstream.id
+	)
+	SELECT id FROM downstream`
+
+// TaskDependencies dependencias directas de una tarea
+type TaskDependencies struct {
+	TaskID   uint          `json:"task_id"`
+	Blockers []models.Task `json:"blockers"` // Tareas que deben completarse antes que esta
+	Blocking []models.Task `json:"blocking"` // Tareas que esperan a que esta se complete
+}
+
+// DependencyRepository define las operaciones sobre las dependencias "A bloquea a B"
+// Agregar o quitar una dependencia incrementa la versión de la tarea bloqueada, ya que cambia su campo blocked.
+type DependencyRepository interface {
+	AddDependency(ctx context.Context, blockerID, blockedID uint) error
+	RemoveDependency(ctx context.Context, blockerID, blockedID uint) error
+	GetDependencies(ctx context.Context, taskID uint) (*TaskDependencies, error)
+	GetPlan(ctx context.Context, projectID *uint) ([]models.Task, error)
+}
+
+// dependencyRepository implementación concreta de DependencyRepository usando GORM
+type dependencyRepository struct {
+	db *gorm.DB
+}
+
+// NewDependencyRepository factory para crear instancias del repositorio de dependencias
+// Recibe: conexión a la base de datos (*gorm.DB)
+// Retorna: implementación de DependencyRepository lista para usar
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepository{db: db}
+}
+
+// AddDependency registra que blockerID bloquea a blockedID
+// Es idempotente: agregar una dependencia existente no produce cambios.
+// Retorna: ErrTaskNotFound si alguna tarea no existe, ErrDependencyCycle si generaría un ciclo, o error de GORM
+func (r *dependencyRepository) AddDependency(ctx context.Context, blockerID, blockedID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, blockedID); err != nil {
+			return err
+		}
+		if err := taskExists(tx, blockerID); err != nil {
+			return err
+		}
+		if err := advisoryLock(tx, dependencyLockKey); err != nil {
+			return err
+		}
+		if err := checkDependency(tx, blockerID, blockedID); err != nil {
+			return err
+		}
+		edge := models.TaskDependency{BlockerID: blockerID, BlockedID: blockedID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&edge)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpTasks(tx.Where("id = ?", blockedID))
+	})
+}
+
+// checkDependency valida que la arista blocker -> blocked mantenga el grafo acíclico:
+// la tarea bloqueada no puede ser la bloqueadora ni bloquearla directa o indirectamente
+// Se ejecuta bajo el bloqueo de dependencias, para que dos altas concurrentes no puedan formar un ciclo.
+func checkDependency(tx *gorm.DB, blockerID, blockedID uint) error {
+	if blockerID == blockedID {
+		return fmt.Errorf("task with ID %d: %w", blockedID, ErrDependencyCycle)
+	}
+	var downstream []uint
+	if err := tx.Raw(downstreamQuery, blockedID).Scan(&downstream).Error; err != nil {
+		return err
+	}
+	for _, id := range downstream {
+		if id == blockerID {
+			return fmt.Errorf("task with ID %d: %w", blockedID, ErrDependencyCycle)
+		}
+	}
+	return nil
+}
+
+// RemoveDependency elimina la dependencia blockerID -> blockedID
+// Es idempotente: quitar una dependencia inexistente no produce cambios.
+// Retorna: ErrTaskNotFound si alguna tarea no existe, o error de GORM
+func (r *dependencyRepository) RemoveDependency(ctx context.Context, blockerID, blockedID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, blockedID); err != nil {
+			return err
+		}
+		if err := taskExists(tx, blockerID); err != nil {
+			return err
+		}
+		result := tx.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.TaskDependency{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpTasks(tx.Where("id = ?", blockedID))
+	})
+}
+
+// GetDependencies obtiene las tareas que bloquean a la tarea y las que ella bloquea, ordenadas por ID
+// Las tareas eliminadas no se incluyen.
+// Retorna: ErrTaskNotFound si la tarea no existe, o error de GORM
+func (r *dependencyRepository) GetDependencies(ctx context.Context, taskID uint) (*TaskDependencies, error) {
+	db := r.db.WithContext(ctx)
+	if err := taskExists(db, taskID); err != nil {
+		return nil, err
+	}
+
+	deps := &TaskDependencies{TaskID: taskID}
+	blockers := db.Model(&models.TaskDependency{}).Select("blocker_id").Where("blocked_id = ?", taskID)
+	if err := db.Preload("Tags", orderTags).Where("id IN (?)", blockers).Order("id").Find(&deps.Blockers).Error; err != nil {
+		return nil, err
+	}
+	blocking := db.Model(&models.TaskDependency{}).Select("blocked_id").Where("blocker_id = ?", taskID)
+	if err := db.Preload("Tags", orderTags).Where("id IN (?)", blocking).Order("id").Find(&deps.Blocking).Error; err != nil {
+		return nil, err
+	}
+
+	if err := markBlockedAll(db, deps.Blockers); err != nil {
+		return nil, err
+	}
+	if err := markBlockedAll(db, deps.Blocking); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// GetPlan obtiene las tareas abiertas (no completadas) en orden topológico:
+// cada tarea aparece después de todas las tareas abiertas que la bloquean.
+// Entre las tareas disponibles en cada paso se elige primero la de mayor prioridad,
+// luego la de fecha límite más cercana (sin fecha al final) y por último la de menor ID.
+// Recibe: ID del proyecto para limitar el plan (nil para todas las tareas de proyectos no archivados)
+// Retorna: las tareas ordenadas, ErrProjectNotFound, o error de GORM
+func (r *dependencyRepository) GetPlan(ctx context.Context, projectID *uint) ([]models.Task, error) {
+	db := r.db.WithContext(ctx)
+	query := db.Preload("Tags", orderTags).Where("tasks.status_category <> ?", models.CategoryDone)
+	if projectID != nil {
+		if err := projectExists(db, *projectID); err != nil {
+			return nil, err
+		}
+		query = query.Where("tasks.project_id = ?", *projectID)
+	} else {
+		query = query.Where(notInArchivedProject)
+	}
+	var tasks []models.Task
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return []models.Task{}, nil
+	}
+	if err := markBlockedAll(db, tasks); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	var edges []models.TaskDependency
+	if err := db.Where("blocker_id IN ? AND blocked_id IN ?", ids, ids).Find(&edges).Error; err != nil {
+		return nil, err
+	}
+	return topologicalOrder(tasks, edges)
+}
+
+// topologicalOrder ordena las tareas con el algoritmo de Kahn usando un heap como cola de disponibles
+// Las aristas cuyos extremos no están en tasks se ignoran.
+func topologicalOrder(tasks []models.Task, edges []models.TaskDependency) ([]models.Task, error) {
+	index := make(map[uint]int, len(tasks))
+	for i := range tasks {
+		index[tasks[i].ID] = i
+	}
+	pending := make([]int, len(tasks)) // Bloqueadores aún no ubicados en el plan
+	next := make(map[uint][]int, len(edges))
+	for _, e := range edges {
+		_, okFrom := index[e.BlockerID]
+		to, okTo := index[e.BlockedID]
+		if !okFrom || !okTo {
+			continue
+		}
+		pending[to]++
+		next[e.BlockerID] = append(next[e.BlockerID], to)
+	}
+
+	ready := &planQueue{tasks: tasks}
+	for i := range tasks {
+		if pending[i] == 0 {
+			ready.items = append(ready.items, i)
+		}
+	}
+	heap.Init(ready)
+
+	plan := make([]models.Task, 0, len(tasks))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		plan = append(plan, tasks[i])
+		for _, j := range next[tasks[i].ID] {
+			if pending[j]--; pending[j] == 0 {
+				heap.Push(ready, j)
+			}
+		}
+	}
+	if len(plan) != len(tasks) {
+		return nil, ErrDependencyCycle // No debería ocurrir: AddDependency impide los ciclos
+	}
+	return plan, nil
+}
+
+// planQueue heap de índices de tareas disponibles, ordenado según GetPlan
+type planQueue struct {
+	tasks []models.Task
+	items []int
+}
+
+func (q *planQueue) Len() int { return len(q.items) }
+
+func (q *planQueue) Less(i, j int) bool {
+	a, b := &q.tasks[q.items[i]], &q.tasks[q.items[j]]
+	if wa, wb := a.Priority.Weight(), b.Priority.Weight(); wa != wb {
+		return wa > wb
+	}
+	switch {
+	case a.DueAt != nil && b.DueAt != nil && !a.DueAt.Equal(*b.DueAt):
+		return a.DueAt.Before(*b.DueAt)
+	case (a.DueAt == nil) != (b.DueAt == nil):
+		return a.DueAt != nil
+	}
+	return a.ID < b.ID
+}
+
+func (q *planQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *planQueue) Push(x interface{}) { q.items = append(q.items, x.(int)) }
+
+func (q *planQueue) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+// openBlockers subconsulta con los IDs de las tareas que tienen al menos un bloqueador
+// no completado y no eliminado
+func openBlockers(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&models.TaskDependency{}).
+		Select("task_dependencies.blocked_id").
+		Joins("JOIN tasks blockers ON blockers.id = task_dependencies.blocker_id").
+		Where("blockers.deleted_at IS NULL AND blockers.status_category <> ?", models.CategoryDone)
+}
+
+// markBlocked completa el campo calculado Blocked de las tareas indicadas
+func markBlocked(db *gorm.DB, tasks ...*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	var blocked []uint
+	if err := openBlockers(db).Where("task_dependencies.blocked_id IN ?", ids).Distinct().Scan(&blocked).Error; err != nil {
+		return err
+	}
+	set := make(map[uint]bool, len(blocked))
+	for _, id := range blocked {
+		set[id] = true
+	}
+	for _, t := range tasks {
+		t.Blocked = set[t.ID]
+	}
+	return nil
+}
+
+// markBlockedAll aplica markBlocked a todas las tareas de un slice
+func markBlockedAll(db *gorm.DB, tasks []models.Task) error {
+	ptrs := make([]*models.Task, len(tasks))
+	for i := range tasks {
+		ptrs[i] = &tasks[i]
+	}
+	return markBlocked(db, ptrs...)
+}
+
+// checkBlockers impide pasar una tarea a un estado de categoría active mientras alguno de sus bloqueadores esté abierto
+// Recibe la transacción de la escritura: los bloqueadores abiertos se leen bloqueados hasta que termina.
+func checkBlockers(tx *gorm.DB, taskID uint) error {
+	var open []uint
+	err := forShare(openBlockers(tx)).Where("task_dependencies.blocked_id = ?", taskID).
+		Pluck("task_dependencies.blocked_id", &open).Error
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("task with ID %d: %w", taskID, ErrTaskBlocked)
+	}
+	return nil
+}
+
+// bumpBlockedBy incrementa la versión de las tareas bloqueadas directamente por la tarea indicada,
+// ya que su campo blocked puede cambiar cuando la bloqueadora se completa, se reabre o se elimina
+func bumpBlockedBy(tx *gorm.DB, blockerID uint) error {
+	blocked := tx.Session(&gorm.Session{NewDB: true}).Model(&models.TaskDependency{}).
+		Select("blocked_id").Where("blocker_id = ?", blockerID)
+	return bumpTasks(tx.Where("id IN (?)", blocked))
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// GetTasks obtiene una página de tareas aplicando filtros, ordenamiento y paginación (ver repository.GetTasks)
+func (r *memoryRepository) GetTasks(ctx context.Context, opts TaskQueryOptions) (*TaskPage, error) {
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+	if err := memoryProjectExists(opts.ProjectID); err != nil {
+		return nil, err
+	}
+	var after []interface{}
+	if opts.Cursor != "" {
+		var err error
+		if after, err = opts.decodeCursor(); err != nil {
+			return nil, err
+		}
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var sorted []sortedTask
+	for _, task := range r.store.liveTasks() {
+		if !opts.matches(task, time.Now()) {
+			continue
+		}
+		values, err := opts.sortValues(task)
+		if err != nil {
+			return nil, err
+		}
+		sorted = append(sorted, sortedTask{task: task, values: values})
+	}
+	var sortErr error
+	sort.SliceStable(sorted, func(i, j int) bool {
+		c, err := opts.compareSortValues(sorted[i].values, sorted[j].values)
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return c < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+	page := &TaskPage{Total: int64(len(sorted)), Limit: opts.Limit, Offset: opts.Offset}
+
+	// Keyset pagination: solo las tareas posteriores a la última de la página anterior
+	if after != nil {
+		i := sort.Search(len(sorted), func(i int) bool {
+			c, err := opts.compareSortValues(sorted[i].values, after)
+			if err != nil && sortErr == nil {
+				sortErr = err
+			}
+			return c > 0
+		})
+		if sortErr != nil {
+			return nil, sortErr
+		}
+		sorted = sorted[i:]
+	}
+	tasks := make([]*models.Task, len(sorted))
+	for i := range sorted {
+		tasks[i] = sorted[i].task
+	}
+	page.Tasks = pageTasks(tasks, opts.Offset, opts.Limit)
+	if len(tasks) > opts.Offset+opts.Limit {
+		page.NextCursor = opts.encodeCursor(&page.Tasks[len(page.Tasks)-1])
+	}
+	return page, nil
+}
+
+// matches indica si la tarea cumple los filtros de la consulta (ver applyFilters)
+func (o *TaskQueryOptions) matches(task *models.Task, now time.Time) bool {
+	if len(o.Status) > 0 && !contains(o.Status, task.Status) {
+		return false
+	}
+	if len(o.Category) > 0 && !contains(o.Category, task.StatusCategory) {
+		return false
+	}
+	if len(o.Priority) > 0 && !contains(o.Priority, task.Priority) {
+		return false
+	}
+	if len(o.Tags) > 0 && !o.matchesTags(task) {
+		return false
+	}
+	if o.CreatedAfter != nil && !task.CreatedAt.After(*o.CreatedAfter) {
+		return false
+	}
+	if o.CreatedBefore != nil && !task.CreatedAt.Before(*o.CreatedBefore) {
+		return false
+	}
+	if o.DueAfter != nil && (task.DueAt == nil || !task.DueAt.After(*o.DueAfter)) {
+		return false
+	}
+	if o.DueBefore != nil && (task.DueAt == nil || !task.DueAt.Before(*o.DueBefore)) {
+		return false
+	}
+	if o.Overdue && (task.DueAt == nil || !task.DueAt.Before(now)) {
+		return false
+	}
+	if (o.Open || o.Overdue) && task.StatusCategory == models.CategoryDone {
+		return false
+	}
+	return true
+}
+
+// matchesTags aplica el filtro de etiquetas (ver taggedTasks)
+func (o *TaskQueryOptions) matchesTags(task *models.Task) bool {
+	has := make(map[string]bool, len(task.Tags))
+	for _, tag := range task.Tags {
+		has[tag.Name] = true
+	}
+	for _, name := range o.Tags {
+		found := has[models.NormalizeTagName(name)]
+		if found && o.TagMatch == TagMatchAny {
+			return true
+		}
+		if !found && o.TagMatch == TagMatchAll {
+			return false
+		}
+	}
+	return o.TagMatch == TagMatchAll
+}
+
+// contains indica si el valor está en la lista
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedTask tarea junto con los valores de sus campos de orden (ver sortValues)
+type sortedTask struct {
+	task   *models.Task
+	values []interface{}
+}
+
+// sortValues valores de los campos de orden de la tarea, con los mismos tipos que decodeCursor
+// Retorna: error si algún valor de la tarea no se puede convertir al tipo de su campo
+func (o *TaskQueryOptions) sortValues(task *models.Task) ([]interface{}, error) {
+	values := make([]interface{}, len(o.Sort))
+	for i, f := range o.Sort {
+		col := sortColumns[f.Field]
+		v, err := col.parse(col.format(task))
+		if err != nil {
+			return nil, fmt.Errorf("task with ID %d: sort by %s: %w", task.ID, f.Field, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// compareSortValues compara dos listas de valores de orden respetando la dirección de cada campo
+// Retorna: negativo si a va antes que b, positivo si va después y 0 si son iguales, o error si los tipos no coinciden
+func (o *TaskQueryOptions) compareSortValues(a, b []interface{}) (int, error) {
+	for i, f := range o.Sort {
+		c, err := compareValues(a[i], b[i])
+		if err != nil {
+			return 0, fmt.Errorf("sort by %s: %w", f.Field, err)
+		}
+		if f.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// compareValues compara dos valores del mismo tipo de campo de orden
+// Retorna: error si los valores no son del mismo tipo o el tipo no se puede ordenar
+func compareValues(a, b interface{}) (int, error) {
+	switch av := a.(type) {
+	case uint64:
+		if bv, ok := b.(uint64); ok {
+			return compareOrdered(av, bv), nil
+		}
+	case int:
+		if bv, ok := b.(int); ok {
+			return compareOrdered(av, bv), nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return compareOrdered(av, bv), nil
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Compare(bv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare sort values of types %T and %T", a, b)
+}
+
+func compareOrdered[T uint64 | int | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// SearchTasks busca tareas por nombre y descripción con la búsqueda por subcadena de searchTerms
+func (r *memoryRepository) SearchTasks(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	terms, err := searchTerms(opts.Query)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var results []SearchResult
+	for _, task := range r.store.liveTasks() {
+		if ok, rank := matchTerms(task, terms); ok {
+			results = append(results, SearchResult{
+				Task:                 *cloneTask(task),
+				Rank:                 rank,
+				NameHighlight:        highlightTerms(task.Name, terms),
+				DescriptionHighlight: highlightTerms(task.Description, terms),
+			})
+		}
+	}
+	// liveTasks está ordenado por ID, que desempata las coincidencias con la misma relevancia
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Rank > results[j].Rank })
+
+	page := &SearchPage{Total: int64(len(results)), Limit: opts.Limit, Offset: opts.Offset}
+	for i := opts.Offset; i < len(results) && i < opts.Offset+opts.Limit; i++ {
+		page.Results = append(page.Results, results[i])
+	}
+	return page, nil
+}
+
+// GetTaskTree obtiene la tarea con todas sus subtareas anidadas y el progreso de cada nodo
+// Retorna: ErrTaskNotFound si la tarea no existe
+func (r *memoryRepository) GetTaskTree(ctx context.Context, id uint) (*TaskTree, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.live(id)
+	if !ok {
+		return nil, fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+	root := &TaskTree{Task: *cloneTask(task), Children: []*TaskTree{}}
+	nodes := map[uint]*TaskTree{id: root}
+	tasks := r.store.liveTasks()
+	// Cada pasada agrega el siguiente nivel de subtareas hasta que no aparecen nodos nuevos
+	for added := true; added; {
+		added = false
+		for _, t := range tasks {
+			if _, seen := nodes[t.ID]; seen || t.ParentID == nil {
+				continue
+			}
+			if parent, ok := nodes[*t.ParentID]; ok {
+				node := &TaskTree{Task: *cloneTask(t), Children: []*TaskTree{}}
+				parent.Children = append(parent.Children, node)
+				nodes[t.ID] = node
+				added = true
+			}
+		}
+	}
+	root.rollUp()
+	return root, nil
+}
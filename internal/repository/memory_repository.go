@@ -0,0 +1,702 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// memoryStore datos de un repositorio en memoria, compartidos por sus copias (ver WithCaller)
+// Las tareas almacenadas no se modifican en su lugar: cada escritura guarda una copia nueva,
+// de modo que revertir un cambio consiste en volver a guardar el puntero anterior.
+type memoryStore struct {
+	mu          sync.Mutex
+	tasks       map[uint]*models.Task // Todas las tareas, incluidas las de la papelera (DeletedAt válido)
+	events      []models.TaskStatusEvent
+	tags        map[string]models.Tag // Etiquetas por nombre normalizado
+	lastTaskID  uint
+	lastEventID uint
+	lastTagID   uint
+	undo        []func() // Cambios a revertir si falla la transacción en curso (nil fuera de una transacción)
+}
+
+// memoryRepository implementación de TaskRepository que guarda los datos en memoria
+type memoryRepository struct {
+	store  *memoryStore
+	rules  TaskRules
+	caller Caller // Origen de los cambios registrados en el historial de estados (ver WithCaller)
+}
+
+// NewMemoryTaskRepository factory para crear un repositorio de tareas en memoria, sin base de datos
+// Recibe: reglas opcionales
+// Retorna: implementación de TaskRepository para desarrollo local y pruebas (los datos se pierden al terminar el proceso)
+// Nota: No hay proyectos, flujos propios ni dependencias: toda tarea usa models.DefaultWorkflow,
+// un project_id produce ErrProjectNotFound y el campo blocked es siempre false. Las operaciones no hacen E/S,
+// por lo que ignoran el contexto recibido.
+func NewMemoryTaskRepository(rules TaskRules) TaskRepository {
+	return &memoryRepository{
+		store: &memoryStore{tasks: map[uint]*models.Task{}, tags: map[string]models.Tag{}},
+		rules: rules,
+	}
+}
+
+// WithCaller retorna una copia del repositorio que atribuye a caller los cambios que realiza
+func (r *memoryRepository) WithCaller(caller Caller) TaskRepository {
+	scoped := *r
+	scoped.caller = caller
+	return &scoped
+}
+
+// transaction ejecuta fn y, si retorna un error, revierte los cambios que hizo
+// Las transacciones anidadas se comportan como savepoints. Requiere mu bloqueado.
+func (s *memoryStore) transaction(fn func() error) error {
+	outermost := s.undo == nil
+	if outermost {
+		s.undo = []func(){}
+	}
+	mark := len(s.undo)
+	err := fn()
+	if err != nil {
+		for i := len(s.undo) - 1; i >= mark; i-- {
+			s.undo[i]()
+		}
+		s.undo = s.undo[:mark]
+	}
+	if outermost {
+		s.undo = nil
+	}
+	return err
+}
+
+// journal registra la función que revierte un cambio de la transacción en curso
+func (s *memoryStore) journal(revert func()) {
+	if s.undo != nil {
+		s.undo = append(s.undo, revert)
+	}
+}
+
+// put guarda la tarea (que no debe modificarse después)
+func (s *memoryStore) put(task *models.Task) {
+	id := task.ID
+	previous, existed := s.tasks[id]
+	s.journal(func() {
+		if existed {
+			s.tasks[id] = previous
+		} else {
+			delete(s.tasks, id)
+		}
+	})
+	s.tasks[id] = task
+}
+
+// remove elimina definitivamente la tarea
+func (s *memoryStore) remove(id uint) {
+	if previous, ok := s.tasks[id]; ok {
+		s.journal(func() { s.tasks[id] = previous })
+		delete(s.tasks, id)
+	}
+}
+
+// setEvents reemplaza el historial de estados
+func (s *memoryStore) setEvents(events []models.TaskStatusEvent) {
+	previous := s.events
+	s.journal(func() { s.events = previous })
+	s.events = events
+}
+
+// tag obtiene la etiqueta con el nombre (ya normalizado) indicado, creándola si no existe
+func (s *memoryStore) tag(name string) models.Tag {
+	if tag, ok := s.tags[name]; ok {
+		return tag
+	}
+	now := time.Now()
+	s.lastTagID++
+	tag := models.Tag{ID: s.lastTagID, Name: name, CreatedAt: now, UpdatedAt: now}
+	s.journal(func() { delete(s.tags, name) })
+	s.tags[name] = tag
+	return tag
+}
+
+// live busca una tarea que no esté en la papelera
+func (s *memoryStore) live(id uint) (*models.Task, bool) {
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt.Valid {
+		return nil, false
+	}
+	return task, true
+}
+
+// liveTasks tareas que no están en la papelera, ordenadas por ID
+func (s *memoryStore) liveTasks() []*models.Task {
+	tasks := make([]*models.Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		if !task.DeletedAt.Valid {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// findByName busca la tarea no eliminada con el nombre indicado dentro del proyecto, ignorando la tarea exceptID
+func (s *memoryStore) findByName(projectID *uint, name string, exceptID uint) *models.Task {
+	for _, task := range s.liveTasks() {
+		if task.ID != exceptID && task.Name == name && sameProject(task.ProjectID, projectID) {
+			return task
+		}
+	}
+	return nil
+}
+
+// cloneTask copia la tarea, incluidos los valores de sus campos puntero y sus etiquetas
+func cloneTask(task *models.Task) *models.Task {
+	c := *task
+	c.ProjectID = cloneUint(task.ProjectID)
+	c.ParentID = cloneUint(task.ParentID)
+	c.StartAt = cloneTime(task.StartAt)
+	c.DueAt = cloneTime(task.DueAt)
+	c.Tags = append([]models.Tag{}, task.Tags...)
+	return &c
+}
+
+func cloneUint(v *uint) *uint {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func cloneTime(v *time.Time) *time.Time {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// memoryProjectExists el repositorio en memoria no tiene proyectos: toda referencia a un proyecto es inválida
+func memoryProjectExists(projectID *uint) error {
+	if projectID != nil {
+		return fmt.Errorf("project with ID %d: %w", *projectID, ErrProjectNotFound)
+	}
+	return nil
+}
+
+// CreateTask crea una nueva tarea (ver repository.CreateTask)
+func (r *memoryRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.transaction(func() error { return r.createTask(task) })
+}
+
+func (r *memoryRepository) createTask(task *models.Task) error {
+	if err := memoryProjectExists(task.ProjectID); err != nil {
+		return err
+	}
+	if err := applyWorkflow(models.DefaultWorkflow(), task, ""); err != nil {
+		return err
+	}
+	if task.ParentID != nil {
+		if err := r.checkParent(0, *task.ParentID); err != nil {
+			return err
+		}
+	}
+	// Mismas comprobaciones que el hook BeforeSave de GORM
+	if err := task.BeforeSave(nil); err != nil {
+		return err
+	}
+	if r.store.findByName(task.ProjectID, task.Name, 0) != nil {
+		return ErrDuplicateName
+	}
+
+	now := time.Now()
+	r.store.lastTaskID++
+	task.ID = r.store.lastTaskID
+	task.CreatedAt, task.UpdatedAt = now, now
+	task.DeletedAt = gorm.DeletedAt{}
+	task.Version = 1
+	task.Tags = []models.Tag{}
+	task.Blocked = false
+	r.store.put(cloneTask(task))
+	r.recordStatusEvent(task, nil)
+	return nil
+}
+
+// GetTaskByID busca una tarea por su ID
+// Retorna: ErrTaskNotFound si no existe o está en la papelera
+func (r *memoryRepository) GetTaskByID(ctx context.Context, id uint) (*models.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.getTask(id)
+}
+
+func (r *memoryRepository) getTask(id uint) (*models.Task, error) {
+	task, ok := r.store.live(id)
+	if !ok {
+		return nil, fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+	return cloneTask(task), nil
+}
+
+// memoryColumns columnas que pueden actualizarse con PatchTask
+var memoryColumns = map[string]func(dst, src *models.Task){
+	"name":        func(dst, src *models.Task) { dst.Name = src.Name },
+	"description": func(dst, src *models.Task) { dst.Description = src.Description },
+	"status":      func(dst, src *models.Task) { dst.Status, dst.StatusCategory = src.Status, src.StatusCategory },
+	"priority":    func(dst, src *models.Task) { dst.Priority = src.Priority },
+	"project_id":  func(dst, src *models.Task) { dst.ProjectID = cloneUint(src.ProjectID) },
+	"parent_id":   func(dst, src *models.Task) { dst.ParentID = cloneUint(src.ParentID) },
+	"start_at":    func(dst, src *models.Task) { dst.StartAt = cloneTime(src.StartAt) },
+	"due_at":      func(dst, src *models.Task) { dst.DueAt = cloneTime(src.DueAt) },
+}
+
+// UpdateTask actualiza los campos modificables de la tarea (ver repository.UpdateTask)
+func (r *memoryRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	return r.PatchTask(ctx, task, []string{"name", "description", "status", "priority", "project_id", "parent_id", "start_at", "due_at"})
+}
+
+// PatchTask actualiza únicamente las columnas indicadas (ver repository.PatchTask)
+func (r *memoryRepository) PatchTask(ctx context.Context, task *models.Task, columns []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.transaction(func() error { return r.patchTask(task, columns) })
+}
+
+func (r *memoryRepository) patchTask(task *models.Task, columns []string) error {
+	changed := map[string]bool{}
+	for _, column := range columns {
+		if _, ok := memoryColumns[column]; !ok {
+			return fmt.Errorf("unknown task column %q", column)
+		}
+		changed[column] = true
+	}
+
+	stored, ok := r.store.live(task.ID)
+	if !ok {
+		return fmt.Errorf("task with ID %d: %w", task.ID, ErrTaskNotFound)
+	}
+	if changed["project_id"] {
+		if err := memoryProjectExists(task.ProjectID); err != nil {
+			return err
+		}
+	}
+	if changed["parent_id"] && task.ParentID != nil {
+		if err := r.checkParent(task.ID, *task.ParentID); err != nil {
+			return err
+		}
+	}
+
+	// El estado se valida contra el flujo cuando cambia el estado o el proyecto
+	statusChecked := changed["status"] || changed["project_id"]
+	if statusChecked {
+		from := stored.Status
+		if !sameProject(stored.ProjectID, task.ProjectID) {
+			from = ""
+		}
+		if err := applyWorkflow(models.DefaultWorkflow(), task, from); err != nil {
+			return err
+		}
+		changed["status"] = true
+		if task.StatusCategory == models.CategoryDone && r.rules.RequireSubtasksCompleted {
+			if err := r.checkSubtasksCompleted(task.ID); err != nil {
+				return err
+			}
+		}
+		// Sin dependencias, ninguna tarea está bloqueada: no hay bloqueadores que comprobar
+	}
+	if task.Version != 0 && task.Version != stored.Version {
+		return fmt.Errorf("task with ID %d: %w", task.ID, ErrVersionConflict)
+	}
+
+	updated := cloneTask(stored)
+	for column := range changed {
+		memoryColumns[column](updated, task)
+	}
+	if err := updated.BeforeSave(nil); err != nil {
+		return err
+	}
+	if r.store.findByName(updated.ProjectID, updated.Name, updated.ID) != nil {
+		return ErrDuplicateName
+	}
+	updated.Version++
+	updated.UpdatedAt = time.Now()
+	r.store.put(updated)
+	if statusChecked && stored.Status != updated.Status {
+		from := stored.Status
+		r.recordStatusEvent(updated, &from)
+	}
+
+	// Igual que GORM, se devuelven los valores almacenados (versión, timestamps y etiquetas)
+	*task = *cloneTask(updated)
+	return nil
+}
+
+// DeleteTask mueve la tarea a la papelera (ver repository.DeleteTask)
+func (r *memoryRepository) DeleteTask(ctx context.Context, id uint, expectedVersion uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.transaction(func() error { return r.deleteTask(id, expectedVersion) })
+}
+
+func (r *memoryRepository) deleteTask(id uint, expectedVersion uint) error {
+	stored, ok := r.store.live(id)
+	if !ok {
+		return fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+	if expectedVersion != 0 && stored.Version != expectedVersion {
+		return fmt.Errorf("task with ID %d: %w", id, ErrVersionConflict)
+	}
+	deleted := cloneTask(stored)
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.put(deleted)
+	r.detachTask(stored)
+	return nil
+}
+
+// detachTask las subtareas directas de una tarea que deja de existir pasan a depender de su padre
+func (r *memoryRepository) detachTask(task *models.Task) {
+	now := time.Now()
+	for _, child := range r.store.liveTasks() {
+		if child.ParentID == nil || *child.ParentID != task.ID {
+			continue
+		}
+		moved := cloneTask(child)
+		moved.ParentID = cloneUint(task.ParentID)
+		moved.Version++
+		moved.UpdatedAt = now
+		r.store.put(moved)
+	}
+}
+
+// checkParent valida el nuevo parent_id de una tarea (ver repository.checkParent)
+func (r *memoryRepository) checkParent(taskID, parentID uint) error {
+	if _, ok := r.store.live(parentID); !ok {
+		return models.ValidationErrors{{Field: "parent_id", Message: "parent task does not exist"}}
+	}
+	if taskID == 0 {
+		return nil
+	}
+	visited := map[uint]bool{}
+	for id := parentID; !visited[id]; {
+		if id == taskID {
+			return fmt.Errorf("task with ID %d: %w", taskID, ErrHierarchyCycle)
+		}
+		visited[id] = true
+		ancestor, ok := r.store.tasks[id]
+		if !ok || ancestor.ParentID == nil {
+			break
+		}
+		id = *ancestor.ParentID
+	}
+	return nil
+}
+
+// checkSubtasksCompleted aplica la regla RequireSubtasksCompleted
+func (r *memoryRepository) checkSubtasksCompleted(taskID uint) error {
+	for _, child := range r.store.liveTasks() {
+		if child.ParentID != nil && *child.ParentID == taskID && child.StatusCategory != models.CategoryDone {
+			return fmt.Errorf("task with ID %d: %w", taskID, ErrOpenSubtasks)
+		}
+	}
+	return nil
+}
+
+// recordStatusEvent registra el estado actual de la tarea en el historial
+func (r *memoryRepository) recordStatusEvent(task *models.Task, from *models.Status) {
+	r.store.lastEventID++
+	event := models.TaskStatusEvent{
+		ID:         r.store.lastEventID,
+		TaskID:     task.ID,
+		FromStatus: from,
+		ToStatus:   task.Status,
+		ToCategory: task.StatusCategory,
+		Actor:      r.caller.Actor,
+		CreatedAt:  time.Now(),
+	}
+	events := append(r.store.events[:len(r.store.events):len(r.store.events)], event)
+	r.store.setEvents(events)
+}
+
+// GetTaskHistory obtiene los cambios de estado de la tarea con sus métricas (ver repository.GetTaskHistory)
+func (r *memoryRepository) GetTaskHistory(ctx context.Context, id uint) (*TaskHistory, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	task, ok := r.store.live(id)
+	if !ok {
+		return nil, fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+	events := []models.TaskStatusEvent{}
+	for _, event := range r.store.events {
+		if event.TaskID == id {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return newTaskHistory(task, events, time.Now()), nil
+}
+
+// BulkTasks ejecuta las operaciones en orden de forma atómica (ver repository.BulkTasks)
+func (r *memoryRepository) BulkTasks(ctx context.Context, ops []BulkOperation, opts BulkOptions) ([]BulkResult, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	results := make([]BulkResult, len(ops))
+	err := r.store.transaction(func() error {
+		for i, op := range ops {
+			err := r.store.transaction(func() error {
+				var err error
+				results[i].Action, results[i].Task, err = applyBulk(ctx, memoryTx{r}, op)
+				return err
+			})
+			if err == nil {
+				continue
+			}
+			results[i] = BulkResult{Action: op.Action, Err: err}
+			if opts.Atomic {
+				return &BulkError{Index: i, Err: err}
+			}
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// memoryTx operaciones del repositorio en memoria dentro de un lote, con mu ya bloqueado
+type memoryTx struct {
+	r *memoryRepository
+}
+
+func (tx memoryTx) CreateTask(_ context.Context, task *models.Task) error {
+	return tx.r.createTask(task)
+}
+
+func (tx memoryTx) GetTaskByID(_ context.Context, id uint) (*models.Task, error) {
+	return tx.r.getTask(id)
+}
+
+func (tx memoryTx) DeleteTask(_ context.Context, id uint, expectedVersion uint) error {
+	return tx.r.deleteTask(id, expectedVersion)
+}
+
+func (tx memoryTx) PatchTask(_ context.Context, task *models.Task, columns []string) error {
+	return tx.r.patchTask(task, columns)
+}
+
+// findTaskByName busca la tarea no eliminada con el nombre indicado dentro del proyecto
+func (tx memoryTx) findTaskByName(_ context.Context, projectID *uint, name string) (*models.Task, error) {
+	if task := tx.r.store.findByName(projectID, name, 0); task != nil {
+		return cloneTask(task), nil
+	}
+	return nil, nil
+}
+
+// workflow retorna el flujo de trabajo por defecto, el único que admite el repositorio en memoria
+func (tx memoryTx) workflow(_ context.Context, _ *uint) (*models.Workflow, error) {
+	return models.DefaultWorkflow(), nil
+}
+
+// setTaskTags reemplaza las etiquetas de la tarea por las indicadas (ver repository.setTaskTags)
+func (tx memoryTx) setTaskTags(_ context.Context, task *models.Task, names []string) (*models.Task, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	stored, ok := tx.r.store.live(task.ID)
+	if !ok {
+		return nil, fmt.Errorf("task with ID %d: %w", task.ID, ErrTaskNotFound)
+	}
+
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, tx.r.store.tag(name))
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	if sameTags(stored.Tags, tags) {
+		return task, nil
+	}
+
+	updated := cloneTask(stored)
+	updated.Tags = tags
+	updated.Version++
+	updated.UpdatedAt = time.Now()
+	tx.r.store.put(updated)
+	return cloneTask(updated), nil
+}
+
+// sameTags indica si dos listas de etiquetas ordenadas por nombre son iguales
+func sameTags(a, b []models.Tag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// GetTrash obtiene una página de las tareas eliminadas, de la más reciente a la más antigua
+func (r *memoryRepository) GetTrash(ctx context.Context, opts TrashOptions) (*TaskPage, error) {
+	if err := normalizePage(&opts.Limit, opts.Offset); err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var trash []*models.Task
+	for _, task := range r.store.tasks {
+		if task.DeletedAt.Valid {
+			trash = append(trash, task)
+		}
+	}
+	sort.Slice(trash, func(i, j int) bool {
+		a, b := trash[i], trash[j]
+		if !a.DeletedAt.Time.Equal(b.DeletedAt.Time) {
+			return a.DeletedAt.Time.After(b.DeletedAt.Time)
+		}
+		return a.ID > b.ID
+	})
+	return &TaskPage{Tasks: pageTasks(trash, opts.Offset, opts.Limit), Total: int64(len(trash)), Limit: opts.Limit, Offset: opts.Offset}, nil
+}
+
+// pageTasks copia las tareas de la página indicada
+func pageTasks(tasks []*models.Task, offset, limit int) []models.Task {
+	page := []models.Task{}
+	for i := offset; i < len(tasks) && i < offset+limit; i++ {
+		page = append(page, *cloneTask(tasks[i]))
+	}
+	return page
+}
+
+// GetDeletedTask busca una tarea en la papelera
+// Retorna: ErrTaskNotFound si no existe o no está eliminada
+func (r *memoryRepository) GetDeletedTask(ctx context.Context, id uint) (*models.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.getDeletedTask(id)
+}
+
+func (r *memoryRepository) getDeletedTask(id uint) (*models.Task, error) {
+	task, ok := r.store.tasks[id]
+	if !ok || !task.DeletedAt.Valid {
+		return nil, fmt.Errorf("task with ID %d in trash: %w", id, ErrTaskNotFound)
+	}
+	return cloneTask(task), nil
+}
+
+// RestoreTask recupera una tarea de la papelera (ver repository.RestoreTask)
+func (r *memoryRepository) RestoreTask(ctx context.Context, id uint, name string) (*models.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, err := r.getDeletedTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		task.Name = name
+		if err := task.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := memoryProjectExists(task.ProjectID); err != nil {
+		return nil, err
+	}
+	if task.ParentID != nil {
+		if _, ok := r.store.live(*task.ParentID); !ok {
+			task.ParentID = nil
+		}
+	}
+	if err := restoreStatus(models.DefaultWorkflow(), task); err != nil {
+		return nil, err
+	}
+	if r.store.findByName(task.ProjectID, task.Name, id) != nil {
+		return nil, fmt.Errorf("task with ID %d: %w", id, ErrDuplicateName)
+	}
+
+	task.DeletedAt = gorm.DeletedAt{}
+	task.Version++
+	task.UpdatedAt = time.Now()
+	r.store.put(task)
+	return r.getTask(id)
+}
+
+// PurgeTask elimina definitivamente una tarea, esté en la papelera o no (ver repository.PurgeTask)
+func (r *memoryRepository) PurgeTask(ctx context.Context, id uint, expectedVersion uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.transaction(func() error {
+		task, ok := r.store.tasks[id]
+		if !ok {
+			return fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+		}
+		if expectedVersion != 0 && task.Version != expectedVersion {
+			return fmt.Errorf("task with ID %d: %w", id, ErrVersionConflict)
+		}
+		if !task.DeletedAt.Valid {
+			r.detachTask(task)
+		}
+		r.purgeTasks([]uint{id})
+		return nil
+	})
+}
+
+// PurgeTrash elimina definitivamente las tareas que llevan en la papelera desde antes de la fecha indicada
+// Retorna: IDs de las tareas eliminadas
+func (r *memoryRepository) PurgeTrash(ctx context.Context, before time.Time) ([]uint, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var ids []uint
+	for id, task := range r.store.tasks {
+		if task.DeletedAt.Valid && task.DeletedAt.Time.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	r.purgeTasks(ids)
+	return ids, nil
+}
+
+// purgeTasks elimina definitivamente las tareas indicadas y su historial de estados
+// Las tareas que las tenían como padre quedan en primer nivel.
+func (r *memoryRepository) purgeTasks(ids []uint) {
+	if len(ids) == 0 {
+		return
+	}
+	purged := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		purged[id] = true
+	}
+	for _, task := range r.store.tasks {
+		if task.ParentID != nil && purged[*task.ParentID] && !purged[task.ID] {
+			orphan := cloneTask(task)
+			orphan.ParentID = nil
+			r.store.put(orphan)
+		}
+	}
+	var events []models.TaskStatusEvent
+	for _, event := range r.store.events {
+		if !purged[event.TaskID] {
+			events = append(events, event)
+		}
+	}
+	r.store.setEvents(events)
+	for _, id := range ids {
+		r.store.remove(id)
+	}
+}
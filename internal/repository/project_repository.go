@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// Errores centinela de los proyectos
+var (
+	ErrProjectNotFound  = errors.New("project not found")                       // El proyecto no existe (o fue eliminado)
+	ErrDuplicateProject = errors.New("a project with this name already exists") // Violación del índice único de nombre
+	ErrProjectNotEmpty  = errors.New("project still has tasks")                 // No se puede eliminar un proyecto con tareas
+)
+
+// translateProjectError convierte errores de GORM en los errores centinela de proyectos
+func translateProjectError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrProjectNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateProject
+	}
+	return err
+}
+
+// ProjectRepository define las operaciones CRUD y de archivado de proyectos
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project *models.Project) error
+	GetProjects(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	GetProjectByID(ctx context.Context, id uint) (*models.Project, error)
+	UpdateProject(ctx context.Context, project *models.Project) error
+	SetArchived(ctx context.Context, id uint, archived bool) (*models.Project, error)
+	DeleteProject(ctx context.Context, id uint) error
+}
+
+// projectRepository implementación concreta de ProjectRepository usando GORM
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository factory para crear instancias del repositorio de proyectos
+// Recibe: conexión a la base de datos (*gorm.DB)
+// Retorna: implementación de ProjectRepository lista para usar
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// CreateProject crea un nuevo proyecto
+// Retorna: ErrDuplicateProject si el nombre ya existe, o error de GORM
+func (r *projectRepository) CreateProject(ctx context.Context, project *models.Project) error {
+	return translateProjectError(r.db.WithContext(ctx).Create(project).Error)
+}
+
+// GetProjects obtiene los proyectos ordenados por nombre
+// Recibe: includeArchived para incluir también los proyectos archivados
+func (r *projectRepository) GetProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	query := r.db.WithContext(ctx).Order("name")
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	projects := []models.Project{}
+	if err := query.Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetProjectByID busca un proyecto por su ID
+// Retorna: ErrProjectNotFound si no existe, o error de GORM
+func (r *projectRepository) GetProjectByID(ctx context.Context, id uint) (*models.Project, error) {
+	return findProject(r.db.WithContext(ctx), id)
+}
+
+// findProject busca un proyecto por ID dentro de la conexión o transacción dada
+func findProject(db *gorm.DB, id uint) (*models.Project, error) {
+	var project models.Project
+	if err := db.First(&project, id).Error; err != nil {
+		return nil, fmt.Errorf("project with ID %d: %w", id, translateProjectError(err))
+	}
+	return &project, nil
+}
+
+// UpdateProject actualiza nombre y descripción del proyecto
+// Retorna: ErrProjectNotFound, ErrDuplicateProject o error de GORM
+// Nota: El estado de archivado solo se modifica con SetArchived.
+func (r *projectRepository) UpdateProject(ctx context.Context, project *models.Project) error {
+	db := r.db.WithContext(ctx)
+	result := db.Model(project).Updates(map[string]interface{}{
+		"name":        project.Name,
+		"description": project.Description,
+	})
+	if result.Error != nil {
+		return translateProjectError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("project with ID %d: %w", project.ID, ErrProjectNotFound)
+	}
+	return db.First(project, project.ID).Error
+}
+
+// SetArchived archiva o reactiva un proyecto
+// Las tareas de un proyecto archivado se ocultan de los listados por defecto (ver TaskQueryOptions.IncludeArchived).
+// Retorna: el proyecto actualizado, ErrProjectNotFound o error de GORM
+func (r *projectRepository) SetArchived(ctx context.Context, id uint, archived bool) (*models.Project, error) {
+	db := r.db.WithContext(ctx)
+	project, err := findProject(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if project.Archived() == archived {
+		return project, nil
+	}
+
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+	if err := db.Model(project).Update("archived_at", archivedAt).Error; err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// DeleteProject elimina un proyecto sin tareas junto con su flujo de trabajo propio
+// Las tareas de la papelera también cuentan: restaurarlas requiere que el proyecto exista.
+// Retorna: ErrProjectNotFound, ErrProjectNotEmpty si aún tiene tareas (incluidas las de la papelera), o error de GORM
+func (r *projectRepository) DeleteProject(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findProject(forUpdate(tx), id); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Unscoped().Model(&models.Task{}).Where("project_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("project with ID %d: %w", id, ErrProjectNotEmpty)
+		}
+		if err := deleteWorkflow(tx, id); err != nil {
+			return err
+		}
+		return tx.Delete(&models.Project{}, id).Error
+	})
+}
+
+// projectExists retorna ErrProjectNotFound si el proyecto no existe o fue eliminado
+func projectExists(db *gorm.DB, id uint) error {
+	var ids []uint
+	if err := db.Model(&models.Project{}).Where("id = ?", id).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("project with ID %d: %w", id, ErrProjectNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories repositorios de la aplicación sobre un backend de almacenamiento
+// Los repositorios que el backend no admite son nil (ver NewMemoryRepositories) y sus rutas no se registran.
+type Repositories struct {
+	Tasks         TaskRepository // Con la auditoría de cada escritura cuando Audit no es nil
+	Projects      ProjectRepository
+	Tags          TagRepository
+	Dependencies  DependencyRepository
+	Workflows     WorkflowRepository
+	Audit         AuditRepository
+	CalendarFeeds CalendarFeedRepository // Versiones de los tokens de los feeds iCalendar
+}
+
+// NewRepositories crea todos los repositorios sobre una base de datos (PostgreSQL o SQLite)
+// Recibe: conexión a la base de datos (*gorm.DB), ya migrada, y las reglas opcionales de las tareas
+func NewRepositories(db *gorm.DB, rules TaskRules) *Repositories {
+	return &Repositories{
+		Tasks:         NewAuditedTaskRepository(db, rules),
+		Projects:      NewProjectRepository(db),
+		Tags:          NewTagRepository(db),
+		Dependencies:  NewDependencyRepository(db),
+		Workflows:     NewWorkflowRepository(db),
+		Audit:         NewAuditRepository(db),
+		CalendarFeeds: NewCalendarFeedRepository(db),
+	}
+}
+
+// NewMemoryRepositories crea el repositorio de tareas en memoria (ver NewMemoryTaskRepository)
+// Proyectos, etiquetas, dependencias, flujos de trabajo, auditoría y versiones de los feeds requieren una base de datos
+// y quedan en nil.
+func NewMemoryRepositories(rules TaskRules) *Repositories {
+	return &Repositories{Tasks: NewMemoryTaskRepository(rules)}
+}
@@ -0,0 +1,338 @@
+// Package repositorytest contiene la suite de conformidad que debe superar toda implementación
+// de repository.TaskRepository (PostgreSQL, SQLite, memoria o un backend futuro)
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+)
+
+// missingID ID que ninguna prueba llega a asignar
+const missingID = 1 << 20
+
+// ctx contexto de las llamadas al repositorio en las pruebas
+var ctx = context.Background()
+
+// TestTaskRepository ejecuta la suite de conformidad como subpruebas de t
+// Recibe: función que crea un repositorio vacío (sin tareas) para cada subprueba;
+// puede llamar a t.Skip si el backend no está disponible
+// Cubre el contrato que las capas superiores esperan de cualquier backend:
+//   - CRUD: valores asignados al crear (ID, versión, estado inicial) y escrituras que incrementan la versión
+//   - Errores centinela: ErrTaskNotFound, ErrDuplicateName y ErrVersionConflict (reconocibles con errors.Is)
+//   - Papelera: una tarea eliminada deja de ser visible en las consultas hasta que se restaura
+func TestTaskRepository(t *testing.T, newRepo func(t *testing.T) repository.TaskRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repository.TaskRepository)
+	}{
+		{"CRUD", testCRUD},
+		{"NotFound", testNotFound},
+		{"UniqueName", testUniqueName},
+		{"VersionConflict", testVersionConflict},
+		{"SoftDeleteVisibility", testSoftDeleteVisibility},
+		{"RestoreAndPurge", testRestoreAndPurge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+// createTask crea una tarea de prioridad media y falla la prueba si no se puede crear
+func createTask(t *testing.T, repo repository.TaskRepository, name, description string) *models.Task {
+	t.Helper()
+	task := &models.Task{Name: name, Description: description, Priority: models.PriorityMedium}
+	if err := repo.CreateTask(ctx, task); err != nil {
+		t.Fatalf("create %q: %v", name, err)
+	}
+	return task
+}
+
+// deleteTask mueve la tarea a la papelera y falla la prueba si no se puede eliminar
+func deleteTask(t *testing.T, repo repository.TaskRepository, id uint) {
+	t.Helper()
+	if err := repo.DeleteTask(ctx, id, 0); err != nil {
+		t.Fatalf("delete %d: %v", id, err)
+	}
+}
+
+// taskIDs IDs de las tareas en el orden recibido
+func taskIDs(tasks []models.Task) []uint {
+	ids := make([]uint, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// containsID indica si el ID está en la lista
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func testCRUD(t *testing.T, repo repository.TaskRepository) {
+	due := time.Date(2030, time.June, 1, 12, 0, 0, 0, time.UTC)
+	task := &models.Task{Name: "Write report", Description: "Quarterly numbers", Priority: models.PriorityHigh, DueAt: &due}
+	if err := repo.CreateTask(ctx, task); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if task.ID == 0 || task.Version != 1 || task.CreatedAt.IsZero() {
+		t.Errorf("created task has ID %d, version %d, created_at %v; want ID, version 1 and timestamp", task.ID, task.Version, task.CreatedAt)
+	}
+	if task.Status != models.ToDo || task.StatusCategory != models.CategoryTodo {
+		t.Errorf("created task has status %q (%q), want initial status %q (%q)", task.Status, task.StatusCategory, models.ToDo, models.CategoryTodo)
+	}
+
+	got, err := repo.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != task.Name || got.Description != task.Description || got.Priority != models.PriorityHigh ||
+		got.DueAt == nil || !got.DueAt.Equal(due) || got.Version != 1 {
+		t.Errorf("get = %+v, want the created task", got)
+	}
+
+	// UpdateTask reemplaza los campos modificables de la tarea
+	update := &models.Task{Name: "Write annual report", Status: models.InProgress, Priority: models.PriorityLow}
+	update.ID = task.ID
+	if err := repo.UpdateTask(ctx, update); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if update.Version != 2 || update.StatusCategory != models.CategoryActive || update.DueAt != nil {
+		t.Errorf("updated task has version %d, category %q, due_at %v; want 2, %q and no due date",
+			update.Version, update.StatusCategory, update.DueAt, models.CategoryActive)
+	}
+
+	// PatchTask solo escribe las columnas indicadas
+	update.Description = "Not saved"
+	update.Priority = models.PriorityUrgent
+	if err := repo.PatchTask(ctx, update, []string{"priority"}); err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	got, err = repo.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("get after patch: %v", err)
+	}
+	if got.Version != 3 || got.Priority != models.PriorityUrgent || got.Description != "" || got.Name != "Write annual report" {
+		t.Errorf("get after patch = %+v, want version 3 with only the priority changed", got)
+	}
+
+	page, err := repo.GetTasks(ctx, repository.TaskQueryOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if page.Total != 1 || len(page.Tasks) != 1 || page.Tasks[0].ID != task.ID {
+		t.Errorf("list = %v (total %d), want [%d]", taskIDs(page.Tasks), page.Total, task.ID)
+	}
+
+	if err := repo.DeleteTask(ctx, task.ID, got.Version); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.GetTaskByID(ctx, task.ID); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("get deleted task: err = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func testNotFound(t *testing.T, repo repository.TaskRepository) {
+	ghost := &models.Task{Name: "Ghost", Status: models.ToDo, Priority: models.PriorityMedium}
+	ghost.ID = missingID
+
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"GetTaskByID", func() error { _, err := repo.GetTaskByID(ctx, missingID); return err }},
+		{"GetTaskTree", func() error { _, err := repo.GetTaskTree(ctx, missingID); return err }},
+		{"GetTaskHistory", func() error { _, err := repo.GetTaskHistory(ctx, missingID); return err }},
+		{"UpdateTask", func() error { task := *ghost; return repo.UpdateTask(ctx, &task) }},
+		{"PatchTask", func() error { task := *ghost; return repo.PatchTask(ctx, &task, []string{"priority"}) }},
+		{"DeleteTask", func() error { return repo.DeleteTask(ctx, missingID, 0) }},
+		{"GetDeletedTask", func() error { _, err := repo.GetDeletedTask(ctx, missingID); return err }},
+		{"RestoreTask", func() error { _, err := repo.RestoreTask(ctx, missingID, ""); return err }},
+		{"PurgeTask", func() error { return repo.PurgeTask(ctx, missingID, 0) }},
+	}
+	for _, c := range calls {
+		if err := c.call(); !errors.Is(err, repository.ErrTaskNotFound) {
+			t.Errorf("%s of a missing task: err = %v, want ErrTaskNotFound", c.name, err)
+		}
+	}
+
+	// Una tarea activa no está en la papelera
+	task := createTask(t, repo, "Active", "")
+	if _, err := repo.GetDeletedTask(ctx, task.ID); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("GetDeletedTask of an active task: err = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := repo.RestoreTask(ctx, task.ID, ""); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("RestoreTask of an active task: err = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func testUniqueName(t *testing.T, repo repository.TaskRepository) {
+	first := createTask(t, repo, "Plan sprint", "")
+	second := createTask(t, repo, "Review sprint", "")
+
+	duplicate := &models.Task{Name: "Plan sprint", Priority: models.PriorityLow}
+	if err := repo.CreateTask(ctx, duplicate); !errors.Is(err, repository.ErrDuplicateName) {
+		t.Errorf("create with a used name: err = %v, want ErrDuplicateName", err)
+	}
+
+	second.Name = first.Name
+	if err := repo.PatchTask(ctx, second, []string{"name"}); !errors.Is(err, repository.ErrDuplicateName) {
+		t.Errorf("rename to a used name: err = %v, want ErrDuplicateName", err)
+	}
+	got, err := repo.GetTaskByID(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != "Review sprint" || got.Version != 1 {
+		t.Errorf("failed rename changed the task: %+v", got)
+	}
+
+	// Una tarea puede conservar su propio nombre
+	got.Description = "Same name"
+	if err := repo.PatchTask(ctx, got, []string{"name", "description"}); err != nil {
+		t.Errorf("patch keeping the name: %v", err)
+	}
+}
+
+func testVersionConflict(t *testing.T, repo repository.TaskRepository) {
+	task := createTask(t, repo, "Deploy", "")
+	stale := *task
+
+	task.Priority = models.PriorityHigh
+	if err := repo.PatchTask(ctx, task, []string{"priority"}); err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+
+	stale.Priority = models.PriorityLow
+	if err := repo.PatchTask(ctx, &stale, []string{"priority"}); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("patch with a stale version: err = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.UpdateTask(ctx, &stale); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("update with a stale version: err = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.DeleteTask(ctx, task.ID, stale.Version); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("delete with a stale version: err = %v, want ErrVersionConflict", err)
+	}
+
+	got, err := repo.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Version != 2 || got.Priority != models.PriorityHigh {
+		t.Errorf("rejected writes changed the task: %+v", got)
+	}
+}
+
+func testSoftDeleteVisibility(t *testing.T, repo repository.TaskRepository) {
+	parent := createTask(t, repo, "Release", "")
+	kept := createTask(t, repo, "Changelog", "release notes")
+	deleted := createTask(t, repo, "Tag version", "release tag")
+	for _, child := range []*models.Task{kept, deleted} {
+		child.ParentID = &parent.ID
+		if err := repo.PatchTask(ctx, child, []string{"parent_id"}); err != nil {
+			t.Fatalf("nest %q: %v", child.Name, err)
+		}
+	}
+	deleteTask(t, repo, deleted.ID)
+
+	if _, err := repo.GetTaskByID(ctx, deleted.ID); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("get deleted task: err = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.DeleteTask(ctx, deleted.ID, 0); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("delete twice: err = %v, want ErrTaskNotFound", err)
+	}
+
+	page, err := repo.GetTasks(ctx, repository.TaskQueryOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if ids := taskIDs(page.Tasks); containsID(ids, deleted.ID) || page.Total != 2 {
+		t.Errorf("list = %v (total %d), want the 2 tasks not deleted", ids, page.Total)
+	}
+
+	search, err := repo.SearchTasks(ctx, repository.SearchOptions{Query: "release"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	for _, result := range search.Results {
+		if result.Task.ID == deleted.ID {
+			t.Errorf("search returned the deleted task")
+		}
+	}
+	if search.Total != 2 {
+		t.Errorf("search total = %d, want 2", search.Total)
+	}
+
+	tree, err := repo.GetTaskTree(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("tree: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Task.ID != kept.ID {
+		t.Errorf("tree has %d children, want only %d", len(tree.Children), kept.ID)
+	}
+
+	trash, err := repo.GetTrash(ctx, repository.TrashOptions{})
+	if err != nil {
+		t.Fatalf("trash: %v", err)
+	}
+	if trash.Total != 1 || len(trash.Tasks) != 1 || trash.Tasks[0].ID != deleted.ID || trash.Tasks[0].DeletedAt.Time.IsZero() {
+		t.Errorf("trash = %v (total %d), want [%d] with deleted_at", taskIDs(trash.Tasks), trash.Total, deleted.ID)
+	}
+	got, err := repo.GetDeletedTask(ctx, deleted.ID)
+	if err != nil {
+		t.Fatalf("get from trash: %v", err)
+	}
+	if got.Name != deleted.Name {
+		t.Errorf("get from trash = %+v", got)
+	}
+
+	// El nombre de una tarea eliminada queda libre
+	createTask(t, repo, deleted.Name, "")
+}
+
+func testRestoreAndPurge(t *testing.T, repo repository.TaskRepository) {
+	task := createTask(t, repo, "Old idea", "")
+	deleteTask(t, repo, task.ID)
+
+	// Otra tarea ocupa el nombre: se exige uno nuevo para restaurarla
+	createTask(t, repo, "Old idea", "")
+	if _, err := repo.RestoreTask(ctx, task.ID, ""); !errors.Is(err, repository.ErrDuplicateName) {
+		t.Errorf("restore with a used name: err = %v, want ErrDuplicateName", err)
+	}
+	restored, err := repo.RestoreTask(ctx, task.ID, "Old idea (restored)")
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.ID != task.ID || restored.Name != "Old idea (restored)" || restored.DeletedAt.Valid {
+		t.Errorf("restored task = %+v", restored)
+	}
+	if _, err := repo.GetTaskByID(ctx, task.ID); err != nil {
+		t.Errorf("get restored task: %v", err)
+	}
+	if trash, err := repo.GetTrash(ctx, repository.TrashOptions{}); err != nil || trash.Total != 0 {
+		t.Errorf("trash after restore = %+v, %v; want empty", trash, err)
+	}
+
+	deleteTask(t, repo, task.ID)
+	if err := repo.PurgeTask(ctx, task.ID, 0); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if _, err := repo.GetDeletedTask(ctx, task.ID); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("get purged task: err = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := repo.RestoreTask(ctx, task.ID, "Again"); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("restore purged task: err = %v, want ErrTaskNotFound", err)
+	}
+}
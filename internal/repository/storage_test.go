@@ -0,0 +1,415 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/config"
+	"github.com/abrahamcruzc/task-manager-go/internal/migrations"
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository"
+	"github.com/abrahamcruzc/task-manager-go/internal/repository/repositorytest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// ctx contexto de las llamadas al repositorio en las pruebas
+var ctx = context.Background()
+
+// backend almacenamiento sobre el que se ejecutan las pruebas de TaskRepository
+type backend struct {
+	name string
+	open func(t *testing.T) repository.TaskRepository // Repositorio vacío; omite la prueba si el backend no está disponible
+}
+
+// backends backends seleccionables con STORAGE_DRIVER
+// PostgreSQL solo se prueba si TEST_POSTGRES_DSN indica una base de datos desechable (se vacían sus tablas).
+var backends = []backend{
+	{name: config.StorageMemory, open: func(t *testing.T) repository.TaskRepository {
+		return repository.NewMemoryRepositories(repository.TaskRules{}).Tasks
+	}},
+	{name: config.StorageSQLite, open: func(t *testing.T) repository.TaskRepository {
+		return openSQLite(t).Tasks
+	}},
+	{name: config.StoragePostgres, open: func(t *testing.T) repository.TaskRepository {
+		dsn := os.Getenv("TEST_POSTGRES_DSN")
+		if dsn == "" {
+			t.Skip("TEST_POSTGRES_DSN is not set")
+		}
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+		if err != nil {
+			t.Fatalf("open postgres: %v", err)
+		}
+		if err := db.Exec("DROP TABLE IF EXISTS calendar_feeds, audit_entries, task_status_events, workflow_transitions, workflow_statuses, " +
+			"workflows, task_dependencies, task_tags, tags, tasks, projects, schema_migrations CASCADE").Error; err != nil {
+			t.Fatalf("reset postgres: %v", err)
+		}
+		return migrated(t, db).Tasks
+	}},
+}
+
+// openSQLite crea todos los repositorios sobre una base de datos SQLite vacía, para las pruebas que
+// usan proyectos o flujos de trabajo (el backend en memoria no los admite)
+func openSQLite(t *testing.T) *repository.Repositories {
+	t.Helper()
+	cfg := &config.Config{StorageDriver: config.StorageSQLite, SQLitePath: filepath.Join(t.TempDir(), "tasks.db")}
+	db, err := cfg.InitDb()
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	return migrated(t, db)
+}
+
+// migrated aplica las migraciones y retorna los repositorios sobre la base de datos
+func migrated(t *testing.T, db *gorm.DB) *repository.Repositories {
+	t.Helper()
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return repository.NewRepositories(db, repository.TaskRules{})
+}
+
+// forEachBackend ejecuta la prueba con un repositorio vacío de cada backend
+func forEachBackend(t *testing.T, test func(t *testing.T, repo repository.TaskRepository)) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			test(t, b.open(t))
+		})
+	}
+}
+
+// createTask crea una tarea de prioridad media con el nombre indicado
+func createTask(t *testing.T, repo repository.TaskRepository, name, description string) *models.Task {
+	t.Helper()
+	task := &models.Task{Name: name, Description: description, Priority: models.PriorityMedium}
+	if err := repo.CreateTask(ctx, task); err != nil {
+		t.Fatalf("create %q: %v", name, err)
+	}
+	return task
+}
+
+func TestConformance(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			repositorytest.TestTaskRepository(t, b.open)
+		})
+	}
+}
+
+func TestPatchTaskVersionAndHistory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo repository.TaskRepository) {
+		task := createTask(t, repo, "Deploy", "")
+
+		stale := *task
+		task.Status = models.InProgress
+		if err := repo.PatchTask(ctx, task, []string{"status"}); err != nil {
+			t.Fatalf("patch: %v", err)
+		}
+		if task.Version != 2 || task.StatusCategory != models.CategoryActive {
+			t.Errorf("patched task: version %d, category %q", task.Version, task.StatusCategory)
+		}
+
+		stale.Priority = models.PriorityHigh
+		if err := repo.PatchTask(ctx, &stale, []string{"priority"}); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Errorf("patch stale version: err = %v, want ErrVersionConflict", err)
+		}
+		if err := repo.DeleteTask(ctx, task.ID, 1); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Errorf("delete stale version: err = %v, want ErrVersionConflict", err)
+		}
+
+		history, err := repo.GetTaskHistory(ctx, task.ID)
+		if err != nil {
+			t.Fatalf("history: %v", err)
+		}
+		if n := len(history.Events); n != 2 || history.Events[n-1].ToStatus != models.InProgress || history.StartedAt == nil {
+			t.Errorf("history = %+v, want creation and start events", history)
+		}
+	})
+}
+
+func TestBulkTasks(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo repository.TaskRepository) {
+		ops := []repository.BulkOperation{
+			{Action: repository.BulkCreate, Task: &models.Task{Name: "First", Priority: models.PriorityLow}, Tags: []string{"Home", "errands"}},
+			{Action: repository.BulkCreate, Task: &models.Task{Name: "First", Priority: models.PriorityLow}},
+		}
+		_, err := repo.BulkTasks(ctx, ops, repository.BulkOptions{Atomic: true})
+		var bulkErr *repository.BulkError
+		if !errors.As(err, &bulkErr) || bulkErr.Index != 1 || !errors.Is(err, repository.ErrDuplicateName) {
+			t.Fatalf("atomic bulk: err = %v, want BulkError at 1 wrapping ErrDuplicateName", err)
+		}
+		if page, _ := repo.GetTasks(ctx, repository.TaskQueryOptions{}); page.Total != 0 {
+			t.Errorf("atomic bulk left %d tasks", page.Total)
+		}
+
+		results, err := repo.BulkTasks(ctx, ops[:1], repository.BulkOptions{Atomic: true, DryRun: true})
+		if err != nil || results[0].Err != nil || results[0].Task == nil {
+			t.Fatalf("dry run = %+v, %v", results, err)
+		}
+		if page, _ := repo.GetTasks(ctx, repository.TaskQueryOptions{}); page.Total != 0 {
+			t.Errorf("dry run left %d tasks", page.Total)
+		}
+
+		results, err = repo.BulkTasks(ctx, ops, repository.BulkOptions{})
+		if err != nil || results[0].Err != nil || !errors.Is(results[1].Err, repository.ErrDuplicateName) {
+			t.Fatalf("non-atomic bulk = %+v, %v", results, err)
+		}
+		tagged, err := repo.GetTasks(ctx, repository.TaskQueryOptions{Tags: []string{"home", "errands"}, TagMatch: repository.TagMatchAll})
+		if err != nil || tagged.Total != 1 || len(tagged.Tasks[0].Tags) != 2 {
+			t.Errorf("tasks tagged home and errands = %+v, %v", tagged, err)
+		}
+	})
+}
+
+func TestSearchTasks(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo repository.TaskRepository) {
+		byDescription := createTask(t, repo, "Read book", "Notes for the report")
+		byName := createTask(t, repo, "Write report", "")
+		createTask(t, repo, "Unrelated", "")
+
+		page, err := repo.SearchTasks(ctx, repository.SearchOptions{Query: "report"})
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+		if page.Total != 2 || page.Results[0].Task.ID != byName.ID || page.Results[1].Task.ID != byDescription.ID {
+			t.Fatalf("search results = %+v, want name match ranked first", page)
+		}
+		if page.Results[0].NameHighlight != "Write <mark>report</mark>" {
+			t.Errorf("name highlight = %q", page.Results[0].NameHighlight)
+		}
+		if _, err := repo.SearchTasks(ctx, repository.SearchOptions{Query: " ?! "}); !errors.Is(err, repository.ErrInvalidQuery) {
+			t.Errorf("empty search: err = %v, want ErrInvalidQuery", err)
+		}
+	})
+}
+
+func TestTaskTree(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo repository.TaskRepository) {
+		parent := createTask(t, repo, "Release", "")
+		done := &models.Task{Name: "Changelog", ParentID: &parent.ID, Status: models.Completed, Priority: models.PriorityMedium}
+		if err := repo.CreateTask(ctx, done); err != nil {
+			t.Fatalf("create subtask: %v", err)
+		}
+		open := createTask(t, repo, "Tag version", "")
+		open.ParentID = &parent.ID
+		if err := repo.PatchTask(ctx, open, []string{"parent_id"}); err != nil {
+			t.Fatalf("move subtask: %v", err)
+		}
+
+		tree, err := repo.GetTaskTree(ctx, parent.ID)
+		if err != nil {
+			t.Fatalf("tree: %v", err)
+		}
+		if len(tree.Children) != 2 || tree.Progress != 50 {
+			t.Errorf("tree has %d children and progress %v, want 2 and 50", len(tree.Children), tree.Progress)
+		}
+
+		parent.ParentID = &open.ID
+		if err := repo.PatchTask(ctx, parent, []string{"parent_id"}); !errors.Is(err, repository.ErrHierarchyCycle) {
+			t.Errorf("nest under subtask: err = %v, want ErrHierarchyCycle", err)
+		}
+	})
+}
+
+func TestGetTasksCursorPagination(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo repository.TaskRepository) {
+		var want []uint
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			want = append(want, createTask(t, repo, name, "").ID)
+		}
+
+		var got []uint
+		opts := repository.TaskQueryOptions{Sort: []repository.SortField{{Field: "name", Desc: true}}, Limit: 2}
+		for {
+			page, err := repo.GetTasks(ctx, opts)
+			if err != nil {
+				t.Fatalf("get tasks: %v", err)
+			}
+			for _, task := range page.Tasks {
+				got = append(got, task.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("paged IDs = %v, want %d tasks", got, len(want))
+		}
+		for i := range got {
+			if got[i] != want[len(want)-1-i] {
+				t.Fatalf("paged IDs = %v, want names in descending order", got)
+			}
+		}
+	})
+}
+
+func TestWorkflowKeepsStatusesOfTrashedTasks(t *testing.T) {
+	repos := openSQLite(t)
+	project := &models.Project{Name: "Web"}
+	if err := repos.Projects.CreateProject(ctx, project); err != nil {
+		t.Fatal(err)
+	}
+	task := &models.Task{Name: "Deploy", ProjectID: &project.ID, Status: models.InProgress, Priority: models.PriorityMedium}
+	if err := repos.Tasks.CreateTask(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Tasks.DeleteTask(ctx, task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	workflow := &models.Workflow{Name: "Kanban", Statuses: []models.WorkflowStatus{
+		{Name: "Backlog", Category: models.CategoryTodo},
+		{Name: "Done", Category: models.CategoryDone},
+	}}
+	if err := repos.Workflows.SetProjectWorkflow(ctx, project.ID, workflow); !errors.Is(err, repository.ErrStatusInUse) {
+		t.Errorf("workflow without the status of a trashed task: err = %v, want ErrStatusInUse", err)
+	}
+	restored, err := repos.Tasks.RestoreTask(ctx, task.ID, "")
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.Status != models.InProgress || restored.StatusCategory != models.CategoryActive {
+		t.Errorf("restored task status = %q (%s)", restored.Status, restored.StatusCategory)
+	}
+}
+
+func TestDeleteProjectWithTrashedTasks(t *testing.T) {
+	repos := openSQLite(t)
+	project := &models.Project{Name: "Web"}
+	if err := repos.Projects.CreateProject(ctx, project); err != nil {
+		t.Fatal(err)
+	}
+	task := &models.Task{Name: "Deploy", ProjectID: &project.ID, Priority: models.PriorityMedium}
+	if err := repos.Tasks.CreateTask(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Tasks.DeleteTask(ctx, task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Projects.DeleteProject(ctx, project.ID); !errors.Is(err, repository.ErrProjectNotEmpty) {
+		t.Errorf("project with a trashed task: err = %v, want ErrProjectNotEmpty", err)
+	}
+	if err := repos.Tasks.PurgeTask(ctx, task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Projects.DeleteProject(ctx, project.ID); err != nil {
+		t.Errorf("project without tasks: %v", err)
+	}
+}
+
+func TestReservedTagName(t *testing.T) {
+	repos := openSQLite(t)
+	var errs models.ValidationErrors
+	if err := repos.Tags.CreateTag(ctx, &models.Tag{Name: " Merge "}); !errors.As(err, &errs) {
+		t.Errorf("tag named merge: err = %v, want ValidationErrors", err)
+	}
+	if err := repos.Tags.CreateTag(ctx, &models.Tag{Name: "merged"}); err != nil {
+		t.Errorf("tag named merged: %v", err)
+	}
+}
+
+func TestAuditIsWrittenWithTheChange(t *testing.T) {
+	cfg := &config.Config{StorageDriver: config.StorageSQLite, SQLitePath: filepath.Join(t.TempDir(), "tasks.db")}
+	db, err := cfg.InitDb()
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	repos := migrated(t, db)
+	task := createTask(t, repos.Tasks, "Deploy", "")
+
+	ops := []repository.BulkOperation{{Action: repository.BulkDelete, ID: task.ID}}
+	if _, err := repos.Tasks.BulkTasks(ctx, ops, repository.BulkOptions{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Tasks.BulkTasks(ctx, ops, repository.BulkOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	page, err := repos.Audit.GetAuditEntries(ctx, repository.AuditQuery{TaskID: &task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 2 || page.Entries[1].Action != models.AuditDelete || page.Entries[1].Changes["name"].Before != "Deploy" {
+		t.Errorf("audit entries = %+v, want create and delete (the dry run is not audited)", page.Entries)
+	}
+
+	if err := db.Exec("DROP TABLE audit_entries").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Tasks.CreateTask(ctx, &models.Task{Name: "Unaudited", Priority: models.PriorityLow}); err == nil {
+		t.Error("create without audit table: expected an error")
+	}
+	var count int64
+	db.Unscoped().Model(&models.Task{}).Where("name = ?", "Unaudited").Count(&count)
+	if count != 0 {
+		t.Error("the task was created although its audit entry failed")
+	}
+}
+
+func TestRotateCalendarFeed(t *testing.T) {
+	feeds := openSQLite(t).CalendarFeeds
+	for want := uint(2); want <= 3; want++ {
+		if v, err := feeds.RotateFeed(ctx, "project:1"); err != nil || v != want {
+			t.Fatalf("rotate = %d, %v; want version %d", v, err, want)
+		}
+	}
+	versions, err := feeds.GetFeedVersions(ctx)
+	if err != nil || len(versions) != 1 || versions["project:1"] != 3 {
+		t.Errorf("versions = %v, %v", versions, err)
+	}
+	if v, err := feeds.GetFeedVersion(ctx, "project:1"); err != nil || v != 3 {
+		t.Errorf("version of project:1 = %d, %v; want 3", v, err)
+	}
+	if v, err := feeds.GetFeedVersion(ctx, "all"); err != nil || v != 1 {
+		t.Errorf("version of a feed never rotated = %d, %v; want 1", v, err)
+	}
+}
+
+func TestBulkTasksStatusByCategory(t *testing.T) {
+	repos := openSQLite(t)
+	project := &models.Project{Name: "Web"}
+	if err := repos.Projects.CreateProject(ctx, project); err != nil {
+		t.Fatal(err)
+	}
+	workflow := &models.Workflow{Name: "Kanban", Statuses: []models.WorkflowStatus{
+		{Name: "Backlog", Category: models.CategoryTodo},
+		{Name: "Doing", Category: models.CategoryActive},
+		{Name: "Shipped", Category: models.CategoryDone},
+	}, Transitions: []models.WorkflowTransition{{From: "Backlog", To: "Doing"}, {From: "Doing", To: "Shipped"}}}
+	if err := repos.Workflows.SetProjectWorkflow(ctx, project.ID, workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &models.Task{Name: "Deploy", ProjectID: &project.ID, Priority: models.PriorityMedium}
+	ops := []repository.BulkOperation{{Action: repository.BulkCreate, Task: task, Category: models.CategoryActive}}
+	results, err := repos.Tasks.BulkTasks(ctx, ops, repository.BulkOptions{Atomic: true})
+	if err != nil || results[0].Task.Status != "Doing" {
+		t.Fatalf("create with the active category = %+v, %v; want status Doing", results, err)
+	}
+
+	ops = []repository.BulkOperation{{
+		Action:   repository.BulkUpsert,
+		Task:     &models.Task{Name: "Deploy", ProjectID: &project.ID},
+		Category: models.CategoryDone,
+		Apply:    func(*models.Task) ([]string, error) { return nil, nil },
+	}}
+	results, err = repos.Tasks.BulkTasks(ctx, ops, repository.BulkOptions{Atomic: true})
+	if err != nil || results[0].Action != repository.BulkUpdate || results[0].Task.Status != "Shipped" {
+		t.Errorf("upsert with the done category = %+v, %v; want status Shipped", results, err)
+	}
+}
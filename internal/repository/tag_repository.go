@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errores centinela de las etiquetas
+var (
+	ErrTagNotFound  = errors.New("tag not found")                       // La etiqueta no existe
+	ErrDuplicateTag = errors.New("a tag with this name already exists") // Violación del índice único de nombre
+)
+
+// translateTagError convierte errores de GORM en los errores centinela de etiquetas
+func translateTagError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrTagNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateTag
+	}
+	return err
+}
+
+// taskTag fila de la tabla intermedia de la relación muchos a muchos Task <-> Tag
+type taskTag struct {
+	TaskID uint `gorm:"primaryKey"`
+	TagID  uint `gorm:"primaryKey"`
+}
+
+// TableName nombre de la tabla intermedia creada por GORM para Task.Tags
+func (taskTag) TableName() string { return "task_tags" }
+
+// TagRepository define las operaciones sobre etiquetas y su asignación a tareas
+// Los nombres se reciben sin normalizar; el repositorio aplica models.NormalizeTagName.
+// Toda operación que cambia las etiquetas de una tarea incrementa su versión (y por lo tanto su ETag).
+type TagRepository interface {
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	GetTags(ctx context.Context) ([]models.Tag, error)
+	GetTagByName(ctx context.Context, name string) (*models.Tag, error)
+	RenameTag(ctx context.Context, name, newName string) (*models.Tag, error)
+	MergeTags(ctx context.Context, sources []string, target string) (*models.Tag, error)
+	DeleteTag(ctx context.Context, name string) error
+	AddTaskTag(ctx context.Context, taskID uint, name string) error
+	RemoveTaskTag(ctx context.Context, taskID uint, name string) error
+}
+
+// tagRepository implementación concreta de TagRepository usando GORM
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository factory para crear instancias del repositorio de etiquetas
+// Recibe: conexión a la base de datos (*gorm.DB)
+// Retorna: implementación de TagRepository lista para usar
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// reservedTagNames nombres que coinciden con rutas fijas de /tags (ej. POST /tags/merge) y no pueden usarse
+// como etiqueta, ya que /tags/{tag} no podría distinguirlos
+var reservedTagNames = map[string]bool{"merge": true}
+
+// normalizeTagName normaliza y valida el nombre de una etiqueta
+func normalizeTagName(name string) (string, error) {
+	name = models.NormalizeTagName(name)
+	if err := models.ValidateTagName(name); err != nil {
+		return "", err
+	}
+	if reservedTagNames[name] {
+		return "", models.ValidationErrors{{Field: "name", Message: fmt.Sprintf("tag name %q is reserved", name)}}
+	}
+	return name, nil
+}
+
+// CreateTag crea una nueva etiqueta
+// Retorna: ValidationErrors si el nombre no es válido, ErrDuplicateTag si ya existe, o error de GORM
+func (r *tagRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	name, err := normalizeTagName(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+	return translateTagError(r.db.WithContext(ctx).Create(tag).Error)
+}
+
+// GetTags obtiene todas las etiquetas ordenadas por nombre
+func (r *tagRepository) GetTags(ctx context.Context) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	if err := r.db.WithContext(ctx).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetTagByName busca una etiqueta por su nombre
+// Retorna: ErrTagNotFound si no existe, o error de GORM
+func (r *tagRepository) GetTagByName(ctx context.Context, name string) (*models.Tag, error) {
+	return findTag(r.db.WithContext(ctx), models.NormalizeTagName(name))
+}
+
+// findTag busca una etiqueta por nombre normalizado dentro de la conexión o transacción dada
+func findTag(db *gorm.DB, name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := db.Where("name = ?", name).First(&tag).Error; err != nil {
+		return nil, fmt.Errorf("tag %q: %w", name, translateTagError(err))
+	}
+	return &tag, nil
+}
+
+// RenameTag cambia el nombre de una etiqueta; las tareas etiquetadas reflejan el nuevo nombre
+// Retorna: ErrTagNotFound, ErrDuplicateTag si el nuevo nombre ya existe, ValidationErrors o error de GORM
+// Nota: Para unir la etiqueta con una existente se debe usar MergeTags.
+func (r *tagRepository) RenameTag(ctx context.Context, name, newName string) (*models.Tag, error) {
+	newName, err := normalizeTagName(newName)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag *models.Tag
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if tag, err = findTag(tx, models.NormalizeTagName(name)); err != nil {
+			return err
+		}
+		if tag.Name == newName {
+			return nil
+		}
+		if err := tx.Model(tag).Update("name", newName).Error; err != nil {
+			return translateTagError(err)
+		}
+		return bumpTaggedTasks(tx, tag.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// MergeTags une las etiquetas de origen en la etiqueta destino en una sola transacción:
+// las tareas con alguna etiqueta de origen pasan a tener la etiqueta destino y las de origen se eliminan.
+// La etiqueta destino se crea si no existe.
+// Retorna: la etiqueta destino, ErrTagNotFound si alguna de origen no existe, ValidationErrors o error de GORM
+func (r *tagRepository) MergeTags(ctx context.Context, sources []string, target string) (*models.Tag, error) {
+	target, err := normalizeTagName(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var into models.Tag
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(models.Tag{Name: target}).FirstOrCreate(&into).Error; err != nil {
+			return translateTagError(err)
+		}
+		for _, source := range sources {
+			from, err := findTag(tx, models.NormalizeTagName(source))
+			if err != nil {
+				return err
+			}
+			if from.ID == into.ID {
+				continue
+			}
+			if err := bumpTaggedTasks(tx, from.ID); err != nil {
+				return err
+			}
+			// Las tareas que ya tienen la etiqueta destino conservan una sola asociación
+			err = tx.Exec(`INSERT INTO task_tags (task_id, tag_id)
+				SELECT task_id, ? FROM task_tags WHERE tag_id = ?
+				ON CONFLICT DO NOTHING`, into.ID, from.ID).Error
+			if err != nil {
+				return err
+			}
+			if err := deleteTag(tx, from.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &into, nil
+}
+
+// DeleteTag elimina una etiqueta y la quita de todas las tareas
+// Retorna: ErrTagNotFound si no existe, o error de GORM
+func (r *tagRepository) DeleteTag(ctx context.Context, name string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tag, err := findTag(tx, models.NormalizeTagName(name))
+		if err != nil {
+			return err
+		}
+		if err := bumpTaggedTasks(tx, tag.ID); err != nil {
+			return err
+		}
+		return deleteTag(tx, tag.ID)
+	})
+}
+
+// deleteTag elimina las asociaciones de la etiqueta y luego la etiqueta
+func deleteTag(tx *gorm.DB, id uint) error {
+	if err := tx.Where("tag_id = ?", id).Delete(&taskTag{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.Tag{}, id).Error
+}
+
+// AddTaskTag asigna una etiqueta a una tarea, creando la etiqueta si no existe
+// Es idempotente: asignar una etiqueta que la tarea ya tiene no produce cambios.
+// Retorna: ErrTaskNotFound, ValidationErrors si el nombre no es válido, o error de GORM
+func (r *tagRepository) AddTaskTag(ctx context.Context, taskID uint, name string) error {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, taskID); err != nil {
+			return err
+		}
+		var tag models.Tag
+		if err := tx.Where(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return translateTagError(err)
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&taskTag{TaskID: taskID, TagID: tag.ID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpTasks(tx.Where("id = ?", taskID))
+	})
+}
+
+// RemoveTaskTag quita una etiqueta de una tarea
+// Es idempotente: quitar una etiqueta que la tarea no tiene no produce cambios.
+// Retorna: ErrTaskNotFound, ErrTagNotFound si la etiqueta no existe, o error de GORM
+func (r *tagRepository) RemoveTaskTag(ctx context.Context, taskID uint, name string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskExists(tx, taskID); err != nil {
+			return err
+		}
+		tag, err := findTag(tx, models.NormalizeTagName(name))
+		if err != nil {
+			return err
+		}
+		result := tx.Where("task_id = ? AND tag_id = ?", taskID, tag.ID).Delete(&taskTag{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpTasks(tx.Where("id = ?", taskID))
+	})
+}
+
+// taskExists retorna ErrTaskNotFound si la tarea no existe o fue eliminada
+func taskExists(db *gorm.DB, id uint) error {
+	var ids []uint
+	if err := db.Model(&models.Task{}).Where("id = ?", id).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+	return nil
+}
+
+// bumpTaggedTasks incrementa la versión de todas las tareas que tienen la etiqueta,
+// ya que su representación (y su ETag) cambia al renombrar, unir o eliminar la etiqueta
+func bumpTaggedTasks(tx *gorm.DB, tagID uint) error {
+	tagged := tx.Session(&gorm.Session{NewDB: true}).Model(&taskTag{}).Select("task_id").Where("tag_id = ?", tagID)
+	return bumpTasks(tx.Where("id IN (?)", tagged))
+}
+
+// bumpTasks incrementa la versión y updated_at de las tareas que cumplen las condiciones de query
+// Se omiten los hooks porque no se modifica ningún campo validado por BeforeSave.
+func bumpTasks(query *gorm.DB) error {
+	return query.Session(&gorm.Session{SkipHooks: true}).Model(&models.Task{}).Updates(map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(), // Con SkipHooks GORM no lo asigna automáticamente
+	}).Error
+}
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// BulkAction tipo de operación de un lote
+type BulkAction string
+
+const (
+	BulkCreate BulkAction = "create" // Crear una tarea (ver CreateTask)
+	BulkUpdate BulkAction = "update" // Modificar una tarea existente (ver PatchTask)
+	BulkDelete BulkAction = "delete" // Mover una tarea a la papelera (ver DeleteTask)
+	BulkUpsert BulkAction = "upsert" // Modificar la tarea del proyecto con el mismo nombre o, si no existe, crearla
+)
+
+// BulkOptions modo de ejecución de un lote
+type BulkOptions struct {
+	Atomic bool // El primer error revierte el lote completo; si es false, una operación fallida solo revierte sus propios cambios
+	DryRun bool // Ejecuta y valida el lote pero revierte todos los cambios al terminar
+}
+
+// errDryRun revierte la transacción de un lote de prueba
+var errDryRun = errors.New("dry run")
+
+// BulkOperation operación de un lote
+type BulkOperation struct {
+	Action BulkAction
+	// Create y upsert: tarea a crear (se valida con Validate; sin estado recibe el estado inicial del flujo).
+	// En upsert, su proyecto y nombre identifican a la tarea existente.
+	Task *models.Task
+	// Update y delete: ID de la tarea y versión esperada (0 para no comprobarla)
+	ID      uint
+	Version uint
+	// Update y upsert: modifica la tarea tal como está en ese punto del lote (incluidos los cambios de operaciones
+	// anteriores) y retorna las columnas que cambiaron, o un error (ej. ValidationErrors) que hace fallar la operación
+	Apply func(task *models.Task) ([]string, error)
+	// Create, update y upsert: nombres de las etiquetas que debe tener la tarea; las que falten se crean
+	// y las que sobren se quitan (nil = no se modifican)
+	Tags []string
+	// Create y upsert: categoría del estado cuando el origen solo conoce la categoría (ej. STATUS de iCalendar).
+	// La tarea recibe el primer estado de esa categoría en el flujo de su proyecto, salvo que su estado
+	// ya sea de esa categoría ("" = no se modifica el estado)
+	Category models.StatusCategory
+}
+
+// BulkResult resultado de una operación de un lote
+type BulkResult struct {
+	Action BulkAction   // Acción aplicada (en upsert, create o update)
+	Task   *models.Task // Tarea creada o actualizada (nil en delete o si la operación falló)
+	Err    error        // Error de la operación (nil si se aplicó)
+}
+
+// BulkError operación que hizo revertir un lote atómico
+type BulkError struct {
+	Index int   // Posición de la operación en el lote
+	Err   error // Error de la operación
+}
+
+// Error implementa la interfaz error
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("bulk operation %d: %v", e.Index, e.Err)
+}
+
+// Unwrap permite identificar el error de la operación con errors.Is / errors.As
+func (e *BulkError) Unwrap() error {
+	return e.Err
+}
+
+// bulkTx operaciones de un repositorio sobre la transacción (o savepoint) de una operación del lote
+// Las implementaciones de TaskRepository comparten así la lógica de cada acción (ver applyBulk).
+type bulkTx interface {
+	CreateTask(ctx context.Context, task *models.Task) error
+	GetTaskByID(ctx context.Context, id uint) (*models.Task, error)
+	PatchTask(ctx context.Context, task *models.Task, columns []string) error
+	DeleteTask(ctx context.Context, id uint, expectedVersion uint) error
+	findTaskByName(ctx context.Context, projectID *uint, name string) (*models.Task, error)
+	setTaskTags(ctx context.Context, task *models.Task, names []string) (*models.Task, error)
+	workflow(ctx context.Context, projectID *uint) (*models.Workflow, error)
+}
+
+// BulkTasks ejecuta las operaciones en orden dentro de una sola transacción, cada una en su propio savepoint
+// Recibe: operaciones a ejecutar y modo de ejecución (ver BulkOptions)
+// Retorna:
+//   - un resultado por operación (con su error si falló, en modo no atómico)
+//   - *BulkError con la posición de la operación fallida si el lote atómico se revirtió, o error de GORM
+func (r *repository) BulkTasks(ctx context.Context, ops []BulkOperation, opts BulkOptions) ([]BulkResult, error) {
+	return r.bulkTasks(ctx, ops, opts, func(tasks *repository, op BulkOperation) (BulkAction, *models.Task, error) {
+		return applyBulk(ctx, tasks, op)
+	})
+}
+
+// bulkStep ejecuta una operación del lote con el repositorio ligado a su savepoint
+type bulkStep func(tasks *repository, op BulkOperation) (BulkAction, *models.Task, error)
+
+// bulkTasks implementa BulkTasks ejecutando cada operación con step
+func (r *repository) bulkTasks(ctx context.Context, ops []BulkOperation, opts BulkOptions, step bulkStep) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ops))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, op := range ops {
+			err := tx.Transaction(func(sp *gorm.DB) error {
+				var err error
+				results[i].Action, results[i].Task, err = step(r.withDB(sp), op)
+				return err
+			})
+			if err == nil {
+				continue
+			}
+			results[i] = BulkResult{Action: op.Action, Err: err}
+			if opts.Atomic {
+				return &BulkError{Index: i, Err: err}
+			}
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// withDB retorna una copia del repositorio que opera sobre la conexión o transacción indicada
+func (r *repository) withDB(db *gorm.DB) *repository {
+	scoped := *r
+	scoped.db = db
+	return &scoped
+}
+
+// applyBulk ejecuta una operación del lote
+// Retorna: la acción aplicada, la tarea creada o actualizada, o el error de la operación
+func applyBulk(ctx context.Context, tx bulkTx, op BulkOperation) (BulkAction, *models.Task, error) {
+	action, task, err := applyBulkAction(ctx, tx, op)
+	if err != nil || task == nil || op.Tags == nil {
+		return action, task, err
+	}
+	task, err = tx.setTaskTags(ctx, task, op.Tags)
+	return action, task, err
+}
+
+// applyBulkAction ejecuta la acción de la operación, sin sus etiquetas
+func applyBulkAction(ctx context.Context, tx bulkTx, op BulkOperation) (BulkAction, *models.Task, error) {
+	switch op.Action {
+	case BulkCreate:
+		task, err := bulkCreate(ctx, tx, op.Task, op.Category)
+		return BulkCreate, task, err
+
+	case BulkUpdate:
+		task, err := tx.GetTaskByID(ctx, op.ID)
+		if err != nil {
+			return BulkUpdate, nil, err
+		}
+		if op.Version != 0 && task.Version != op.Version {
+			return BulkUpdate, nil, fmt.Errorf("task with ID %d: %w", op.ID, ErrVersionConflict)
+		}
+		task, err = bulkUpdate(ctx, tx, task, op.Apply, "")
+		return BulkUpdate, task, err
+
+	case BulkUpsert:
+		if op.Task == nil {
+			return BulkUpsert, nil, models.ValidationErrors{{Field: "task", Message: "task is required"}}
+		}
+		existing, err := tx.findTaskByName(ctx, op.Task.ProjectID, op.Task.Name)
+		if err != nil {
+			return BulkUpsert, nil, err
+		}
+		if existing == nil {
+			task, err := bulkCreate(ctx, tx, op.Task, op.Category)
+			return BulkCreate, task, err
+		}
+		task, err := bulkUpdate(ctx, tx, existing, op.Apply, op.Category)
+		return BulkUpdate, task, err
+
+	case BulkDelete:
+		return BulkDelete, nil, tx.DeleteTask(ctx, op.ID, op.Version)
+	}
+	return op.Action, nil, models.ValidationErrors{{
+		Field: "action",
+		Message: fmt.Sprintf("invalid action %q: must be one of %s, %s, %s, %s",
+			op.Action, BulkCreate, BulkUpdate, BulkUpsert, BulkDelete),
+	}}
+}
+
+// bulkCreate valida y crea la tarea de una operación create o upsert
+// Si se indica una categoría y la tarea no tiene estado, recibe el primer estado de esa categoría (ver BulkOperation).
+func bulkCreate(ctx context.Context, tx bulkTx, task *models.Task, category models.StatusCategory) (*models.Task, error) {
+	if task == nil {
+		return nil, models.ValidationErrors{{Field: "task", Message: "task is required"}}
+	}
+	if category != "" && task.Status == "" {
+		status, err := categoryStatus(ctx, tx, task, category)
+		if err != nil {
+			return nil, err
+		}
+		task.Status = status
+	}
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	if err := tx.CreateTask(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// bulkUpdate aplica la función de modificación a la tarea y persiste las columnas que cambiaron
+// Si se indica una categoría, la tarea pasa al primer estado de esa categoría salvo que ya tenga uno (ver BulkOperation).
+func bulkUpdate(ctx context.Context, tx bulkTx, task *models.Task, apply func(*models.Task) ([]string, error),
+	category models.StatusCategory) (*models.Task, error) {
+	if apply == nil {
+		return nil, errors.New("bulk update without Apply function")
+	}
+	columns, err := apply(task)
+	if err != nil {
+		return nil, err
+	}
+	if category != "" {
+		status, err := categoryStatus(ctx, tx, task, category)
+		if err != nil {
+			return nil, err
+		}
+		if status != task.Status {
+			task.Status = status
+			columns = append(columns, "status")
+		}
+	}
+	if len(columns) > 0 {
+		if err := tx.PatchTask(ctx, task, columns); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// categoryStatus estado que recibe la tarea con la categoría indicada: el suyo si ya es de esa categoría
+// en el flujo de su proyecto, o el primero de esa categoría
+// Retorna: ValidationErrors si el flujo no tiene estados de esa categoría, o error de GORM
+func categoryStatus(ctx context.Context, tx bulkTx, task *models.Task, category models.StatusCategory) (models.Status, error) {
+	workflow, err := tx.workflow(ctx, task.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	if current, ok := workflow.Status(task.Status); ok && current.Category == category {
+		return task.Status, nil
+	}
+	status, ok := workflow.FirstOfCategory(category)
+	if !ok {
+		return "", models.ValidationErrors{{
+			Field:   "status",
+			Message: fmt.Sprintf("workflow %q has no status with the %s category", workflow.Name, category),
+		}}
+	}
+	return status, nil
+}
+
+// workflow obtiene el flujo de trabajo del proyecto (nil = sin proyecto)
+func (r *repository) workflow(ctx context.Context, projectID *uint) (*models.Workflow, error) {
+	return loadWorkflow(r.db.WithContext(ctx), projectID)
+}
+
+// findTaskByName busca la tarea no eliminada con el nombre indicado dentro del proyecto (nil = sin proyecto)
+// Retorna: la tarea, nil si no existe, o error de GORM
+func (r *repository) findTaskByName(ctx context.Context, projectID *uint, name string) (*models.Task, error) {
+	query := r.db.WithContext(ctx).Model(&models.Task{}).Where("name = ?", name)
+	if projectID == nil {
+		query = query.Where("project_id IS NULL")
+	} else {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	var ids []uint
+	if err := query.Limit(1).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.GetTaskByID(ctx, ids[0])
+}
+
+// setTaskTags reemplaza las etiquetas de la tarea por las indicadas, creando las que no existen
+// Retorna: la tarea con sus etiquetas y versión actualizadas, ValidationErrors si algún nombre no es válido,
+// o error de GORM
+func (r *repository) setTaskTags(ctx context.Context, task *models.Task, names []string) (*models.Task, error) {
+	db := r.db.WithContext(ctx)
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	wanted := map[string]bool{}
+	current := map[string]bool{}
+	for _, tag := range task.Tags {
+		current[tag.Name] = true
+	}
+	for _, name := range names {
+		if !current[name] {
+			missing = append(missing, name)
+		}
+		wanted[name] = true
+	}
+
+	changed := len(missing) > 0
+	for _, tag := range task.Tags {
+		if wanted[tag.Name] {
+			continue
+		}
+		if err := db.Where("task_id = ? AND tag_id = ?", task.ID, tag.ID).Delete(&taskTag{}).Error; err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	for _, name := range missing {
+		var tag models.Tag
+		if err := db.Where(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, translateTagError(err)
+		}
+		if err := db.Create(&taskTag{TaskID: task.ID, TagID: tag.ID}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if !changed {
+		return task, nil
+	}
+	if err := bumpTasks(db.Where("id = ?", task.ID)); err != nil {
+		return nil, err
+	}
+	return r.GetTaskByID(ctx, task.ID)
+}
+
+// normalizeTagNames normaliza y valida los nombres de etiqueta de una operación del lote
+// Retorna: los nombres sin duplicados, en el orden indicado, o ValidationErrors con un error por nombre inválido
+func normalizeTagNames(names []string) ([]string, error) {
+	var errs models.ValidationErrors
+	var normalized []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		n, err := normalizeTagName(name)
+		var tagErrs models.ValidationErrors
+		if errors.As(err, &tagErrs) {
+			errs.Add("tags", fmt.Sprintf("invalid tag %q: %s", name, tagErrs[0].Message))
+			continue
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// Errores centinela de la jerarquía de tareas
+var (
+	ErrHierarchyCycle = errors.New("a task cannot be nested under itself or one of its subtasks") // parent_id generaría un ciclo
+	ErrOpenSubtasks   = errors.New("task has subtasks that are not completed")                    // Regla RequireSubtasksCompleted
+)
+
+// TaskTree nodo del árbol de subtareas con el progreso acumulado
+type TaskTree struct {
+	Task     models.Task `json:"task"`
+	Progress float64     `json:"progress"` // Porcentaje de avance (0-100)
+	Children []*TaskTree `json:"children"`
+}
+
+// subtreeQuery CTE recursiva con los IDs de la tarea y todos sus descendientes no eliminados.
+// UNION (en lugar de UNION ALL) garantiza que termine aunque existiera un ciclo en los datos.
+const subtreeQuery = `
+	WITH RECURSIVE subtree(id) AS (
+		SELECT id FROM tasks WHERE id = ? AND deleted_at IS NULL
+		UNION
+		SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+		WHERE tasks.deleted_at IS NULL
+	)
+	SELECT id FROM subtree`
+
+// ancestorsQuery CTE recursiva con los IDs de la tarea y todos sus ancestros
+const ancestorsQuery = `
+	WITH RECURSIVE ancestors(id, parent_id) AS (
+		SELECT id, parent_id FROM tasks WHERE id = ?
+		UNION
+		SELECT tasks.id, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+	)
+	SELECT id FROM ancestors`
+
+// GetTaskTree obtiene la tarea con todas sus subtareas anidadas y el progreso de cada nodo
+// Retorna: ErrTaskNotFound si la tarea no existe, o error de GORM
+func (r *repository) GetTaskTree(ctx context.Context, id uint) (*TaskTree, error) {
+	db := r.db.WithContext(ctx)
+	var ids []uint
+	if err := db.Raw(subtreeQuery, id).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+
+	var tasks []models.Task
+	if err := db.Preload("Tags", orderTags).Order("tasks.id").Find(&tasks, ids).Error; err != nil {
+		return nil, err
+	}
+	if err := markBlockedAll(db, tasks); err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint]*TaskTree, len(tasks))
+	for i := range tasks {
+		nodes[tasks[i].ID] = &TaskTree{Task: tasks[i], Children: []*TaskTree{}}
+	}
+	for _, node := range nodes {
+		if node.Task.ID == id || node.Task.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*node.Task.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	root := nodes[id]
+	root.rollUp()
+	return root, nil
+}
+
+// rollUp calcula el progreso de cada nodo: una tarea sin subtareas vale 100 si su estado es de categoría done
+// y 0 si no; una tarea con subtareas vale el promedio de sus hijas.
+// También ordena los hijos por ID para una respuesta estable.
+func (n *TaskTree) rollUp() float64 {
+	if len(n.Children) == 0 {
+		if n.Task.StatusCategory == models.CategoryDone {
+			n.Progress = 100
+		}
+		return n.Progress
+	}
+
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Task.ID < n.Children[j].Task.ID })
+	var sum float64
+	for _, child := range n.Children {
+		sum += child.rollUp()
+	}
+	n.Progress = sum / float64(len(n.Children))
+	return n.Progress
+}
+
+// checkParent valida el nuevo parent_id de una tarea:
+// la tarea padre debe existir y no puede ser la propia tarea ni uno de sus descendientes
+// Recibe: transacción de la escritura, ID de la tarea (0 si aún no existe) e ID del nuevo padre
+// Toma el bloqueo de jerarquía, de modo que dos cambios concurrentes no puedan formar un ciclo
+// ni colgar la tarea de un padre que se está eliminando.
+// Retorna: ValidationErrors si el padre no existe, ErrHierarchyCycle, o error de GORM
+func checkParent(tx *gorm.DB, taskID, parentID uint) error {
+	if err := advisoryLock(tx, hierarchyLockKey); err != nil {
+		return err
+	}
+	if err := taskExists(tx, parentID); err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return models.ValidationErrors{{Field: "parent_id", Message: "parent task does not exist"}}
+		}
+		return err
+	}
+	if taskID == 0 {
+		return nil
+	}
+
+	var ancestors []uint
+	if err := tx.Raw(ancestorsQuery, parentID).Scan(&ancestors).Error; err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor == taskID {
+			return fmt.Errorf("task with ID %d: %w", taskID, ErrHierarchyCycle)
+		}
+	}
+	return nil
+}
+
+// checkSubtasksCompleted aplica la regla RequireSubtasksCompleted:
+// una tarea no puede pasar a un estado de categoría done mientras tenga subtareas abiertas
+// Las subtareas se leen bloqueadas, bajo el bloqueo de jerarquía para que no se añadan otras mientras tanto.
+func checkSubtasksCompleted(tx *gorm.DB, taskID uint) error {
+	if err := advisoryLock(tx, hierarchyLockKey); err != nil {
+		return err
+	}
+	var open []uint
+	err := forShare(tx.Model(&models.Task{})).
+		Where("parent_id = ? AND status_category <> ?", taskID, models.CategoryDone).
+		Pluck("id", &open).Error
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("task with ID %d: %w", taskID, ErrOpenSubtasks)
+	}
+	return nil
+}
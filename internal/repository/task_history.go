@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// TaskHistory historial de estados de una tarea con sus métricas de tiempo
+// Los tiempos se expresan en segundos; las métricas de una tarea no completada son nil.
+type TaskHistory struct {
+	TaskID           uint                      `json:"task_id"`
+	Events           []models.TaskStatusEvent  `json:"events"`                 // Cambios de estado en orden cronológico
+	StartedAt        *time.Time                `json:"started_at"`             // Primera vez que salió de un estado todo
+	CompletedAt      *time.Time                `json:"completed_at"`           // Entrada en el estado done actual
+	LeadTimeSeconds  *float64                  `json:"lead_time_seconds"`      // Desde la creación hasta completarse
+	CycleTimeSeconds *float64                  `json:"cycle_time_seconds"`     // Desde el inicio del trabajo hasta completarse
+	TimeInStatus     map[models.Status]float64 `json:"time_in_status_seconds"` // Tiempo acumulado en cada estado (el actual hasta ahora)
+}
+
+// GetTaskHistory obtiene los cambios de estado de la tarea y calcula lead time, cycle time
+// y el tiempo acumulado en cada estado
+// Retorna: ErrTaskNotFound si la tarea no existe, o error de GORM
+func (r *repository) GetTaskHistory(ctx context.Context, id uint) (*TaskHistory, error) {
+	db := r.db.WithContext(ctx)
+	var task models.Task
+	if err := db.Select("id", "created_at", "status", "status_category").First(&task, id).Error; err != nil {
+		return nil, fmt.Errorf("task with ID %d: %w", id, translateError(err))
+	}
+	events := []models.TaskStatusEvent{}
+	if err := db.Where("task_id = ?", id).Order("created_at, id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return newTaskHistory(&task, events, time.Now()), nil
+}
+
+// newTaskHistory calcula las métricas a partir de los eventos ordenados cronológicamente
+func newTaskHistory(task *models.Task, events []models.TaskStatusEvent, now time.Time) *TaskHistory {
+	history := &TaskHistory{TaskID: task.ID, Events: events, TimeInStatus: map[models.Status]float64{}}
+
+	for i, event := range events {
+		end := now
+		if i+1 < len(events) {
+			end = events[i+1].CreatedAt
+		}
+		history.TimeInStatus[event.ToStatus] += end.Sub(event.CreatedAt).Seconds()
+
+		if history.StartedAt == nil && event.ToCategory != models.CategoryTodo {
+			at := event.CreatedAt
+			history.StartedAt = &at
+		}
+	}
+
+	// La tarea se completó al entrar en la racha final de estados done
+	if task.StatusCategory != models.CategoryDone {
+		return history
+	}
+	for i := len(events) - 1; i >= 0 && events[i].ToCategory == models.CategoryDone; i-- {
+		at := events[i].CreatedAt
+		history.CompletedAt = &at
+	}
+	if history.CompletedAt == nil {
+		return history
+	}
+	lead := history.CompletedAt.Sub(task.CreatedAt).Seconds()
+	history.LeadTimeSeconds = &lead
+	if history.StartedAt != nil {
+		cycle := history.CompletedAt.Sub(*history.StartedAt).Seconds()
+		history.CycleTimeSeconds = &cycle
+	}
+	return history
+}
+
+// recordStatusEvent registra el estado actual de la tarea en el historial
+// Recibe: la transacción, la tarea ya escrita y su estado anterior (nil al crearla)
+func (r *repository) recordStatusEvent(tx *gorm.DB, task *models.Task, from *models.Status) error {
+	event := models.TaskStatusEvent{
+		TaskID:     task.ID,
+		FromStatus: from,
+		ToStatus:   task.Status,
+		ToCategory: task.StatusCategory,
+		Actor:      r.caller.Actor,
+	}
+	return tx.Create(&event).Error
+}
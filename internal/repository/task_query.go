@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultPageSize = 50  // Tamaño de página cuando el cliente no indica limit
+	MaxPageSize     = 200 // Tamaño máximo de página permitido
+)
+
+// ErrInvalidQuery se retorna cuando las opciones de consulta no son válidas
+// (campo de ordenamiento desconocido, cursor corrupto, etc.)
+var ErrInvalidQuery = errors.New("invalid query")
+
+// SortField representa un criterio de ordenamiento sobre un campo de la tarea
+type SortField struct {
+	Field string // Nombre público del campo (name, status, priority, created_at, updated_at, start_at, due_at)
+	Desc  bool   // true para orden descendente
+}
+
+// TagMatch define cómo se combinan varios filtros de etiqueta
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any" // La tarea tiene al menos una de las etiquetas (por defecto)
+	TagMatchAll TagMatch = "all" // La tarea tiene todas las etiquetas
+)
+
+// TaskQueryOptions agrupa los filtros, ordenamiento y paginación para listar tareas
+// Se admite paginación por offset o por cursor, pero no ambas a la vez
+type TaskQueryOptions struct {
+	ProjectID       *uint                   // Solo tareas del proyecto (incluye las de proyectos archivados)
+	IncludeArchived bool                    // Incluye las tareas de proyectos archivados (ocultas por defecto)
+	Status          []models.Status         // Filtra por uno o varios estados
+	Category        []models.StatusCategory // Filtra por categoría de estado (todo, active, done)
+	Priority        []models.Priority       // Filtra por una o varias prioridades
+	Tags            []string                // Filtra por nombre de etiqueta
+	TagMatch        TagMatch                // Semántica del filtro de etiquetas (any o all)
+	CreatedAfter    *time.Time              // Solo tareas creadas después de esta fecha
+	CreatedBefore   *time.Time              // Solo tareas creadas antes de esta fecha
+	DueAfter        *time.Time              // Solo tareas con fecha límite posterior a esta fecha
+	DueBefore       *time.Time              // Solo tareas con fecha límite anterior a esta fecha
+	Overdue         bool                    // Solo tareas no completadas cuya fecha límite ya pasó
+	Open            bool                    // Excluye las tareas completadas (categoría done)
+	Sort            []SortField             // Criterios de ordenamiento (por defecto: DefaultSort)
+	Limit           int                     // Máximo de tareas por página (0 = DefaultPageSize)
+	Offset          int                     // Desplazamiento para paginación por offset
+	Cursor          string                  // Cursor opaco retornado por una página anterior
+}
+
+// TaskPage es el resultado paginado de GetTasks
+type TaskPage struct {
+	Tasks      []models.Task // Tareas de la página actual
+	Total      int64         // Total de tareas que cumplen los filtros (sin paginar)
+	Limit      int           // Límite efectivo aplicado
+	Offset     int           // Offset efectivo aplicado
+	NextCursor string        // Cursor para la siguiente página ("" si no hay más)
+}
+
+// DefaultSort ordenamiento aplicado cuando el cliente no indica uno:
+// mayor prioridad primero y, dentro de cada prioridad, la fecha límite más próxima
+var DefaultSort = []SortField{{Field: "priority", Desc: true}, {Field: "due_at"}}
+
+// sortColumn describe cómo ordenar por un campo y cómo serializar su valor en el cursor
+type sortColumn struct {
+	expr   string                              // Expresión SQL usada en ORDER BY y en el filtro del cursor
+	vars   []interface{}                       // Parámetros de la expresión (placeholders ? en expr)
+	format func(t *models.Task) string         // Extrae el valor del campo para el cursor
+	parse  func(s string) (interface{}, error) // Convierte el valor del cursor al tipo de la columna
+}
+
+// farFuture sustituye a las fechas nulas al ordenar, de modo que las tareas sin fecha queden al final
+// en orden ascendente y el cursor pueda comparar valores concretos
+var farFuture = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+func formatTime(t time.Time) string { return t.UTC().Format(time.RFC3339Nano) }
+
+// formatOptionalTime formatea una fecha opcional usando farFuture para nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return formatTime(farFuture)
+	}
+	return formatTime(*t)
+}
+
+func parseTime(s string) (interface{}, error) { return time.Parse(time.RFC3339Nano, s) }
+
+func parseString(s string) (interface{}, error) { return s, nil }
+
+// priorityWeightExpr expresión CASE que traduce la prioridad a su peso numérico (low=1 ... urgent=4),
+// de modo que el ordenamiento siga la importancia y no el orden alfabético
+func priorityWeightExpr() string {
+	var b strings.Builder
+	b.WriteString("CASE tasks.priority")
+	for _, value := range models.Priority("").ValidValues() {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", value, models.Priority(value).Weight())
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
+
+// sortColumns lista blanca de campos por los que se puede ordenar
+var sortColumns = map[string]sortColumn{
+	"id": {
+		expr:   "tasks.id",
+		format: func(t *models.Task) string { return strconv.FormatUint(uint64(t.ID), 10) },
+		parse:  func(s string) (interface{}, error) { return strconv.ParseUint(s, 10, 64) },
+	},
+	"name": {
+		expr:   "tasks.name",
+		format: func(t *models.Task) string { return t.Name },
+		parse:  parseString,
+	},
+	"status": {
+		expr:   "tasks.status",
+		format: func(t *models.Task) string { return string(t.Status) },
+		parse:  parseString,
+	},
+	"priority": {
+		expr:   priorityWeightExpr(),
+		format: func(t *models.Task) string { return strconv.Itoa(t.Priority.Weight()) },
+		parse:  func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	},
+	"created_at": {
+		expr:   "tasks.created_at",
+		format: func(t *models.Task) string { return formatTime(t.CreatedAt) },
+		parse:  parseTime,
+	},
+	"updated_at": {
+		expr:   "tasks.updated_at",
+		format: func(t *models.Task) string { return formatTime(t.UpdatedAt) },
+		parse:  parseTime,
+	},
+	"start_at": {
+		expr:   "COALESCE(tasks.start_at, ?)",
+		vars:   []interface{}{farFuture},
+		format: func(t *models.Task) string { return formatOptionalTime(t.StartAt) },
+		parse:  parseTime,
+	},
+	"due_at": {
+		expr:   "COALESCE(tasks.due_at, ?)",
+		vars:   []interface{}{farFuture},
+		format: func(t *models.Task) string { return formatOptionalTime(t.DueAt) },
+		parse:  parseTime,
+	},
+}
+
+// ParseSort interpreta una lista de campos separada por comas
+// Formatos aceptados: "name", "-name" (descendente), "name:asc", "name:desc"
+// Retorna: criterios de ordenamiento o ErrInvalidQuery si algún campo no es válido
+func ParseSort(raw string) ([]SortField, error) {
+	var fields []SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: part[1:], Desc: true}
+		} else if name, dir, ok := strings.Cut(part, ":"); ok {
+			field.Field = name
+			switch strings.ToLower(dir) {
+			case "asc":
+			case "desc":
+				field.Desc = true
+			default:
+				return nil, fmt.Errorf("%w: invalid sort direction %q", ErrInvalidQuery, dir)
+			}
+		}
+
+		if _, ok := sortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, field.Field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// normalizePage aplica el tamaño de página por defecto y el máximo permitido
+// Retorna: ErrInvalidQuery si el offset es negativo
+func normalizePage(limit *int, offset int) error {
+	if *limit <= 0 {
+		*limit = DefaultPageSize
+	}
+	if *limit > MaxPageSize {
+		*limit = MaxPageSize
+	}
+	if offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// normalize aplica valores por defecto y garantiza un orden total agregando id como desempate
+func (o *TaskQueryOptions) normalize() error {
+	if err := normalizePage(&o.Limit, o.Offset); err != nil {
+		return err
+	}
+	switch o.TagMatch {
+	case "":
+		o.TagMatch = TagMatchAny
+	case TagMatchAny, TagMatchAll:
+	default:
+		return fmt.Errorf("%w: tag_match must be %q or %q", ErrInvalidQuery, TagMatchAny, TagMatchAll)
+	}
+	if o.Offset > 0 && o.Cursor != "" {
+		return fmt.Errorf("%w: offset and cursor cannot be combined", ErrInvalidQuery)
+	}
+
+	if len(o.Sort) == 0 {
+		o.Sort = DefaultSort
+	}
+
+	hasID := false
+	for _, f := range o.Sort {
+		if _, ok := sortColumns[f.Field]; !ok {
+			return fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, f.Field)
+		}
+		if f.Field == "id" {
+			hasID = true
+		}
+	}
+	if !hasID {
+		o.Sort = append(append([]SortField(nil), o.Sort...), SortField{Field: "id"})
+	}
+	return nil
+}
+
+// sortKey representación canónica del ordenamiento, usada para validar cursores
+func (o *TaskQueryOptions) sortKey() string {
+	parts := make([]string, len(o.Sort))
+	for i, f := range o.Sort {
+		parts[i] = f.Field
+		if f.Desc {
+			parts[i] = "-" + f.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// cursor contenido serializado de un cursor de paginación
+type cursor struct {
+	Sort   string   `json:"s"` // Ordenamiento con el que se generó el cursor
+	Values []string `json:"v"` // Valores de los campos de orden de la última tarea
+}
+
+// encodeCursor genera el cursor que apunta a la posición posterior a la tarea dada
+func (o *TaskQueryOptions) encodeCursor(last *models.Task) string {
+	c := cursor{Sort: o.sortKey(), Values: make([]string, len(o.Sort))}
+	for i, f := range o.Sort {
+		c.Values[i] = sortColumns[f.Field].format(last)
+	}
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor valida el cursor contra el ordenamiento actual y convierte sus valores
+func (o *TaskQueryOptions) decodeCursor() ([]interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(o.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || len(c.Values) != len(o.Sort) {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	if c.Sort != o.sortKey() {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", ErrInvalidQuery)
+	}
+
+	values := make([]interface{}, len(c.Values))
+	for i, f := range o.Sort {
+		v, err := sortColumns[f.Field].parse(c.Values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// applyFilters agrega las condiciones WHERE de los filtros (sin paginación)
+func (o *TaskQueryOptions) applyFilters(db *gorm.DB) *gorm.DB {
+	if o.ProjectID != nil {
+		db = db.Where("tasks.project_id = ?", *o.ProjectID)
+	} else if !o.IncludeArchived {
+		db = db.Where(notInArchivedProject)
+	}
+	if len(o.Status) > 0 {
+		db = db.Where("tasks.status IN ?", o.Status)
+	}
+	if len(o.Category) > 0 {
+		db = db.Where("tasks.status_category IN ?", o.Category)
+	}
+	if len(o.Priority) > 0 {
+		db = db.Where("tasks.priority IN ?", o.Priority)
+	}
+	if len(o.Tags) > 0 {
+		db = db.Where("tasks.id IN (?)", o.taggedTasks(db))
+	}
+	if o.CreatedAfter != nil {
+		db = db.Where("tasks.created_at > ?", *o.CreatedAfter)
+	}
+	if o.CreatedBefore != nil {
+		db = db.Where("tasks.created_at < ?", *o.CreatedBefore)
+	}
+	if o.DueAfter != nil {
+		db = db.Where("tasks.due_at > ?", *o.DueAfter)
+	}
+	if o.DueBefore != nil {
+		db = db.Where("tasks.due_at < ?", *o.DueBefore)
+	}
+	if o.Overdue {
+		db = db.Where("tasks.due_at < ?", time.Now())
+	}
+	if o.Open || o.Overdue {
+		db = db.Where("tasks.status_category <> ?", models.CategoryDone)
+	}
+	return db
+}
+
+// notInArchivedProject condición que excluye las tareas de proyectos archivados
+const notInArchivedProject = "(tasks.project_id IS NULL OR tasks.project_id NOT IN " +
+	"(SELECT projects.id FROM projects WHERE projects.archived_at IS NOT NULL))"
+
+// taggedTasks subconsulta con los IDs de las tareas que cumplen el filtro de etiquetas
+// any: tienen alguna de las etiquetas; all: tienen todas (se cuentan las etiquetas distintas que coinciden)
+func (o *TaskQueryOptions) taggedTasks(db *gorm.DB) *gorm.DB {
+	names := make([]string, 0, len(o.Tags))
+	seen := make(map[string]bool, len(o.Tags))
+	for _, name := range o.Tags {
+		name = models.NormalizeTagName(name)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	sub := db.Session(&gorm.Session{NewDB: true}).Table("task_tags").
+		Select("task_tags.task_id").
+		Joins("JOIN tags ON tags.id = task_tags.tag_id").
+		Where("tags.name IN ?", names)
+	if o.TagMatch == TagMatchAll {
+		sub = sub.Group("task_tags.task_id").Having("COUNT(DISTINCT task_tags.tag_id) = ?", len(names))
+	}
+	return sub
+}
+
+// applyCursor agrega la condición de keyset pagination:
+// (f1 > v1) OR (f1 = v1 AND f2 > v2) OR ... respetando la dirección de cada campo
+func (o *TaskQueryOptions) applyCursor(db *gorm.DB) (*gorm.DB, error) {
+	values, err := o.decodeCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	var clauses []string
+	var args []interface{}
+	for i, f := range o.Sort {
+		var parts []string
+		for j := 0; j < i; j++ {
+			col := sortColumns[o.Sort[j].Field]
+			parts = append(parts, col.expr+" = ?")
+			args = append(append(args, col.vars...), values[j])
+		}
+		op := ">"
+		if f.Desc {
+			op = "<"
+		}
+		col := sortColumns[f.Field]
+		parts = append(parts, fmt.Sprintf("%s %s ?", col.expr, op))
+		args = append(append(args, col.vars...), values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return db.Where(strings.Join(clauses, " OR "), args...), nil
+}
+
+// applyOrder agrega ORDER BY según los criterios normalizados.
+// Se construye una sola expresión porque GORM reemplaza las cláusulas ORDER BY basadas en expresiones.
+func (o *TaskQueryOptions) applyOrder(db *gorm.DB) *gorm.DB {
+	terms := make([]string, 0, len(o.Sort))
+	var vars []interface{}
+	for _, f := range o.Sort {
+		dir := "ASC"
+		if f.Desc {
+			dir = "DESC"
+		}
+		col := sortColumns[f.Field]
+		terms = append(terms, col.expr+" "+dir)
+		vars = append(vars, col.vars...)
+	}
+	return db.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(terms, ", "), Vars: vars, WithoutParentheses: true}})
+}
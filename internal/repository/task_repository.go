@@ -1,99 +1,429 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/abrahamcruzc/task-manager-go/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Errores centinela retornados por el repositorio
+// Permiten a las capas superiores distinguir los modos de fallo con errors.Is
+var (
+	ErrTaskNotFound    = errors.New("task not found")                       // La tarea no existe (o fue eliminada)
+	ErrDuplicateName   = errors.New("a task with this name already exists") // Violación del índice único (proyecto, nombre)
+	ErrVersionConflict = errors.New("task was modified concurrently")       // La versión esperada no coincide con la almacenada
+)
+
+// translateError convierte errores de GORM en los errores centinela del repositorio
+// Los errores sin traducción se retornan sin cambios
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrTaskNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateName
+	}
+	return err
+}
+
 // TaskRepository define la interfaz para las operaciones CRUD de tareas
 // Contrato que garantiza la implementación de los métodos esenciales
 type TaskRepository interface {
-	CreateTask(task *models.Task) error
-	GetTasks() ([]models.Task, error)
-	GetTaskByID(id uint) (*models.Task, error)
-	UpdateTask(task *models.Task) error
-	DeleteTask(id uint) error
+	CreateTask(ctx context.Context, task *models.Task) error
+	GetTasks(ctx context.Context, opts TaskQueryOptions) (*TaskPage, error)
+	SearchTasks(ctx context.Context, opts SearchOptions) (*SearchPage, error)
+	GetTaskByID(ctx context.Context, id uint) (*models.Task, error)
+	GetTaskTree(ctx context.Context, id uint) (*TaskTree, error)
+	UpdateTask(ctx context.Context, task *models.Task) error
+	PatchTask(ctx context.Context, task *models.Task, columns []string) error
+	DeleteTask(ctx context.Context, id uint, expectedVersion uint) error
+	BulkTasks(ctx context.Context, ops []BulkOperation, opts BulkOptions) ([]BulkResult, error)
+	GetTaskHistory(ctx context.Context, id uint) (*TaskHistory, error)
+	GetTrash(ctx context.Context, opts TrashOptions) (*TaskPage, error)
+	GetDeletedTask(ctx context.Context, id uint) (*models.Task, error)
+	RestoreTask(ctx context.Context, id uint, name string) (*models.Task, error)
+	PurgeTask(ctx context.Context, id uint, expectedVersion uint) error
+	PurgeTrash(ctx context.Context, before time.Time) ([]uint, error)
+	WithCaller(caller Caller) TaskRepository
+}
+
+// TaskRules reglas de negocio opcionales que aplica el repositorio al escribir tareas
+type TaskRules struct {
+	RequireSubtasksCompleted bool // Una tarea no puede pasar a un estado de categoría done con subtareas abiertas
+}
+
+// Caller identifica a quien origina una escritura, para el historial de estados y la auditoría
+type Caller struct {
+	Actor     string // Autor indicado por el cliente (header X-Actor), "" si no se conoce
+	RequestID string // ID de la solicitud HTTP que originó el cambio
 }
 
 // repository implementación concreta de TaskRepository
 // Encapsula la conexión a la base de datos usando GORM
 type repository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	rules  TaskRules
+	caller Caller // Origen de los cambios registrados en el historial de estados (ver WithCaller)
 }
 
 // NewTaskRepository factory para crear instancias del repositorio
-// Recibe: conexión a la base de datos (*gorm.DB)
+// Recibe: conexión a la base de datos (*gorm.DB) y reglas opcionales
 // Retorna: implementación de TaskRepository lista para usar
-func NewTaskRepository(db *gorm.DB) TaskRepository {
-	return &repository{db: db}
+func NewTaskRepository(db *gorm.DB, rules TaskRules) TaskRepository {
+	return &repository{db: db, rules: rules}
+}
+
+// WithCaller retorna una copia del repositorio que atribuye a caller los cambios que realiza
+func (r *repository) WithCaller(caller Caller) TaskRepository {
+	scoped := *r
+	scoped.caller = caller
+	return &scoped
 }
 
 // CreateTask crea una nueva tarea en la base de datos
-// Recibe: puntero a modelo Task
-// Retorna: error de GORM si falla la operación
-func (r *repository) CreateTask(task *models.Task) error {
-	return r.db.Create(task).Error
+// Recibe: puntero a modelo Task (se completan ID y timestamps)
+// Retorna: ErrDuplicateName si el nombre ya existe en el proyecto, ErrProjectNotFound,
+// ValidationErrors si la tarea padre no existe o el estado no pertenece al flujo del proyecto, o error de GORM
+// Nota: Las etiquetas no se crean junto con la tarea; se asignan con TagRepository.AddTaskTag.
+// Sin estado, la tarea recibe el estado inicial del flujo de trabajo del proyecto.
+func (r *repository) CreateTask(ctx context.Context, task *models.Task) error {
+	task.Version = 1 // Toda tarea nueva inicia en la versión 1
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if task.ProjectID != nil {
+			if err := projectExists(forShare(tx), *task.ProjectID); err != nil {
+				return err
+			}
+		}
+		if err := resolveStatus(tx, task, ""); err != nil {
+			return err
+		}
+		if task.ParentID != nil {
+			if err := checkParent(tx, 0, *task.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit(clause.Associations).Create(task).Error; err != nil {
+			return translateError(err)
+		}
+		return r.recordStatusEvent(tx, task, nil)
+	})
+	if err != nil {
+		return err
+	}
+	task.Tags = []models.Tag{}
+	return nil
 }
 
-// GetTasks obtiene todas las tareas almacenadas
-// Retorna: slice de tareas y error de GORM si ocurre
-func (r *repository) GetTasks() ([]models.Task, error) {
+// GetTasks obtiene una página de tareas aplicando filtros, ordenamiento y paginación
+// Recibe: opciones de consulta (ver TaskQueryOptions)
+// Retorna:
+//   - Página con las tareas, el total filtrado y el cursor de la siguiente página
+//   - ErrInvalidQuery si las opciones no son válidas, ErrProjectNotFound, o error de GORM
+func (r *repository) GetTasks(ctx context.Context, opts TaskQueryOptions) (*TaskPage, error) {
+	db := r.db.WithContext(ctx)
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+	if opts.ProjectID != nil {
+		if err := projectExists(db, *opts.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Total de registros que cumplen los filtros, sin paginación
+	var total int64
+	if err := opts.applyFilters(db.Model(&models.Task{})).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query := opts.applyOrder(opts.applyFilters(db.Model(&models.Task{})))
+	if opts.Cursor != "" {
+		var err error
+		if query, err = opts.applyCursor(query); err != nil {
+			return nil, err
+		}
+	}
+
+	// Se pide un registro extra para saber si existe una página siguiente
 	var tasks []models.Task
-	if result := r.db.Find(&tasks); result.Error != nil {
-		return nil, result.Error
+	if err := query.Preload("Tags", orderTags).Offset(opts.Offset).Limit(opts.Limit + 1).Find(&tasks).Error; err != nil {
+		return nil, err
 	}
-	return tasks, nil
+
+	page := &TaskPage{Total: total, Limit: opts.Limit, Offset: opts.Offset}
+	if len(tasks) > opts.Limit {
+		tasks = tasks[:opts.Limit]
+		page.NextCursor = opts.encodeCursor(&tasks[len(tasks)-1])
+	}
+	if err := markBlockedAll(db, tasks); err != nil {
+		return nil, err
+	}
+	page.Tasks = tasks
+	return page, nil
 }
 
 // GetTaskByID busca una tarea por su ID
 // Recibe: ID de la tarea (uint)
-// Retorna: 
+// Retorna:
 //   - Tarea encontrada o nil
-//   - error detallado (incluye ErrRecordNotFound si no existe el registro)
-func (r *repository) GetTaskByID(id uint) (*models.Task, error) {
+//   - ErrTaskNotFound si no existe el registro, o error de GORM
+func (r *repository) GetTaskByID(ctx context.Context, id uint) (*models.Task, error) {
+	db := r.db.WithContext(ctx)
 	var task models.Task
-	result := r.db.First(&task, id)
-	
-	// Manejo específico para registro no encontrado
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("task with ID %d not found: %w", id, result.Error)
+	if err := db.Preload("Tags", orderTags).First(&task, id).Error; err != nil {
+		return nil, fmt.Errorf("task with ID %d: %w", id, translateError(err))
 	}
-	
-	if result.Error != nil {
-		return nil, result.Error
+	if err := markBlocked(db, &task); err != nil {
+		return nil, err
 	}
 	return &task, nil
 }
 
 // UpdateTask actualiza una tarea existente usando actualización parcial
-// Recibe: puntero a modelo Task con los campos a actualizar
-// Retorna: error de GORM si falla la operación
-// Nota: Usa Updates con mapa para evitar sobrescritura de campos no modificados
-func (r *repository) UpdateTask(task *models.Task) error {
-	result := r.db.Model(task).Updates(map[string]interface{}{
+// Recibe: puntero a modelo Task con los campos a actualizar.
+// Si task.Version es distinto de 0, solo se actualiza si coincide con la versión almacenada.
+// Retorna:
+//   - ErrTaskNotFound si el ID no existe, ErrDuplicateName si el nombre ya está en uso en el proyecto
+//   - ErrProjectNotFound si el proyecto indicado no existe
+//   - ErrHierarchyCycle si el nuevo padre es la propia tarea o una de sus subtareas
+//   - ValidationErrors si el estado no pertenece al flujo del proyecto, ErrInvalidTransition si el flujo no permite el cambio
+//   - ErrOpenSubtasks si pasa a un estado done con subtareas abiertas y la regla RequireSubtasksCompleted está activa
+//   - ErrTaskBlocked si pasa a un estado active mientras alguna tarea que la bloquea no está completada
+//   - ErrVersionConflict si la versión almacenada cambió
+//   - error de GORM si falla la operación
+//
+// Nota: Usa Updates con mapa para evitar sobrescritura de campos no modificados.
+// Tras actualizar, la tarea se vuelve a leer para reflejar los valores almacenados (ej. timestamps).
+func (r *repository) UpdateTask(ctx context.Context, task *models.Task) error {
+	return r.updateTask(ctx, task, map[string]interface{}{
 		"name":        task.Name,
 		"description": task.Description,
 		"status":      task.Status,
+		"priority":    task.Priority,
+		"project_id":  task.ProjectID,
+		"parent_id":   task.ParentID,
+		"start_at":    task.StartAt,
+		"due_at":      task.DueAt,
 	})
-	return result.Error
 }
 
-// DeleteTask elimina una tarea por su ID
-// Recibe: ID de la tarea (uint)
-// Retorna: 
-//   - error de GORM si falla la operación
-//   - error personalizado si el ID no existe
-// Valida que se afectó al menos 1 registro con RowsAffected
-func (r *repository) DeleteTask(id uint) error {
-	result := r.db.Delete(&models.Task{}, id)
-	if result.Error != nil {
-		return result.Error
+// PatchTask actualiza únicamente las columnas indicadas con los valores de la tarea
+// Recibe:
+//   - puntero a modelo Task con el estado completo ya validado (task.Version es la versión esperada)
+//   - nombres de columna a actualizar (ej. "status"); updated_at y version se actualizan siempre
+//
+// Retorna: ErrTaskNotFound, ErrDuplicateName, ErrVersionConflict o error de GORM
+func (r *repository) PatchTask(ctx context.Context, task *models.Task, columns []string) error {
+	db := r.db.WithContext(ctx)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(task); err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("task with ID %d not found", id)
+
+	values := make(map[string]interface{}, len(columns))
+	taskValue := reflect.ValueOf(task)
+	for _, column := range columns {
+		field := stmt.Schema.LookUpField(column)
+		if field == nil {
+			return fmt.Errorf("unknown task column %q", column)
+		}
+		values[field.DBName], _ = field.ValueOf(db.Statement.Context, taskValue)
 	}
-	return nil
-}
\ No newline at end of file
+	return r.updateTask(ctx, task, values)
+}
+
+// updateTask aplica los valores indicados incrementando la versión de la tarea
+// Si task.Version es distinto de 0 se exige que coincida con la versión almacenada (optimistic locking)
+// Las comprobaciones se hacen en la misma transacción que la escritura, para que una escritura concurrente
+// no las invalide antes de aplicar los cambios.
+func (r *repository) updateTask(ctx context.Context, task *models.Task, values map[string]interface{}) error {
+	db := r.db.WithContext(ctx)
+	_, statusChanged := values["status"]
+	_, moved := values["project_id"]
+	_, reparented := values["parent_id"]
+	expected := task.Version
+	values["version"] = gorm.Expr("version + 1")
+
+	var previous models.Task
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// El estado anterior se lee con la fila bloqueada, para que el evento de estado registre el estado
+		// realmente reemplazado aunque otra petición actualice la tarea al mismo tiempo
+		if statusChanged || moved {
+			err := forUpdate(tx).Select("id", "project_id", "status", "status_category").First(&previous, task.ID).Error
+			if err != nil {
+				return fmt.Errorf("task with ID %d: %w", task.ID, translateError(err))
+			}
+		}
+		// El proyecto se lee bloqueado para que su flujo no cambie antes de guardar el estado (ver lockProjectTasks)
+		if (moved || statusChanged) && task.ProjectID != nil {
+			if err := projectExists(forShare(tx), *task.ProjectID); err != nil {
+				return err
+			}
+		}
+		if reparented && task.ParentID != nil {
+			if err := checkParent(tx, task.ID, *task.ParentID); err != nil {
+				return err
+			}
+		}
+
+		// El estado se valida contra el flujo del proyecto cuando cambia el estado o el proyecto
+		if statusChanged || moved {
+			// Al cambiar de proyecto no hay transición que validar: el estado debe existir en el nuevo flujo
+			from := previous.Status
+			if !sameProject(previous.ProjectID, task.ProjectID) {
+				from = ""
+			}
+			if err := resolveStatus(tx, task, from); err != nil {
+				return err
+			}
+			values["status"] = task.Status
+			values["status_category"] = task.StatusCategory
+
+			if task.StatusCategory == models.CategoryDone && r.rules.RequireSubtasksCompleted {
+				if err := checkSubtasksCompleted(tx, task.ID); err != nil {
+					return err
+				}
+			}
+			if task.StatusCategory == models.CategoryActive && previous.StatusCategory != models.CategoryActive {
+				if err := checkBlockers(tx, task.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		query := tx.Model(task).Omit(clause.Associations)
+		if expected != 0 {
+			query = query.Where("version = ?", expected)
+		}
+		result := query.Updates(values)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, task.ID)
+		}
+		if previous.ID == 0 {
+			return nil
+		}
+		if previous.Status != task.Status {
+			if err := r.recordStatusEvent(tx, task, &previous.Status); err != nil {
+				return err
+			}
+		}
+		// Completar o reabrir la tarea cambia el campo blocked de las tareas que bloquea
+		if (previous.StatusCategory == models.CategoryDone) != (task.StatusCategory == models.CategoryDone) {
+			return bumpBlockedBy(tx, task.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Releer el registro almacenado para devolver created_at/updated_at y versión reales
+	if err := db.Preload("Tags", orderTags).First(task, task.ID).Error; err != nil {
+		return fmt.Errorf("task with ID %d: %w", task.ID, translateError(err))
+	}
+	return markBlocked(db, task)
+}
+
+// sameProject indica si dos referencias a proyecto apuntan al mismo proyecto (o ambas a ninguno)
+func sameProject(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// orderTags ordena las etiquetas precargadas de cada tarea por nombre
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
+
+// missingOrConflict determina por qué una escritura condicionada no afectó filas:
+// la tarea no existe (ErrTaskNotFound) o su versión cambió (ErrVersionConflict)
+func missingOrConflict(db *gorm.DB, id uint) error {
+	var count int64
+	if err := db.Model(&models.Task{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("task with ID %d: %w", id, ErrTaskNotFound)
+	}
+	return fmt.Errorf("task with ID %d: %w", id, ErrVersionConflict)
+}
+
+// forUpdate bloquea las filas que lee la consulta hasta que termina la transacción (SELECT ... FOR UPDATE),
+// para que lo comprobado antes de una escritura no cambie por una escritura concurrente
+// SQLite ignora la cláusula: no tiene bloqueos de fila, pero sus transacciones se ejecutan de a una (ver config.InitDb).
+func forUpdate(tx *gorm.DB) *gorm.DB {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// forShare bloquea las filas que lee la consulta contra cambios hasta que termina la transacción
+// (SELECT ... FOR SHARE), sin impedir que otras transacciones las lean del mismo modo
+func forShare(tx *gorm.DB) *gorm.DB {
+	return tx.Clauses(clause.Locking{Strength: "SHARE"})
+}
+
+// Claves de los bloqueos consultivos (ver advisoryLock)
+const (
+	hierarchyLockKey  int64 = 1 // Cambios en la jerarquía de tareas (parent_id)
+	dependencyLockKey int64 = 2 // Altas de dependencias entre tareas
+)
+
+// advisoryLock serializa las transacciones que toman la misma clave hasta que terminan (pg_advisory_xact_lock)
+// Se usa cuando la comprobación abarca filas que aún no existen o que no se conocen de antemano (ej. ciclos),
+// y no basta con bloquear las filas leídas. Se toma después de bloquear la fila de la tarea que se escribe.
+// SQLite no lo necesita: sus transacciones de escritura ya se ejecutan de a una.
+func advisoryLock(tx *gorm.DB, key int64) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", key).Error
+}
+
+// DeleteTask elimina una tarea por su ID (la mueve a la papelera, ver RestoreTask y PurgeTask)
+// Recibe:
+//   - ID de la tarea (uint)
+//   - versión esperada (0 para eliminar sin importar la versión)
+//
+// Retorna:
+//   - error de GORM si falla la operación
+//   - ErrTaskNotFound si el ID no existe, ErrVersionConflict si la versión cambió
+//
+// Las subtareas directas pasan a depender del padre de la tarea eliminada (o quedan en primer nivel).
+// Las dependencias se conservan, pero una tarea eliminada deja de bloquear a otras.
+// Valida que se afectó al menos 1 registro con RowsAffected
+func (r *repository) DeleteTask(ctx context.Context, id uint, expectedVersion uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := forUpdate(tx).Select("id", "parent_id").First(&task, id).Error; err != nil {
+			return fmt.Errorf("task with ID %d: %w", id, translateError(err))
+		}
+		if err := advisoryLock(tx, hierarchyLockKey); err != nil {
+			return err
+		}
+
+		query := tx
+		if expectedVersion != 0 {
+			query = query.Where("version = ?", expectedVersion)
+		}
+		result := query.Delete(&models.Task{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("task with ID %d: %w", id, ErrVersionConflict)
+		}
+
+		// Reasignar las subtareas para no dejar referencias a una tarea eliminada
+		return detachTask(tx, &task)
+	})
+}
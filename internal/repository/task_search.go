@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+)
+
+// searchConfig configuración de texto de PostgreSQL usada para indexar y consultar.
+// Se usa 'simple' (sin stemming ni stopwords) porque las tareas mezclan español e inglés.
+const searchConfig = "simple"
+
+// searchVector expresión tsvector sobre nombre (peso A) y descripción (peso B).
+// Sin calificar las columnas, debe coincidir con la expresión del índice GIN idx_tasks_search
+// (internal/migrations/postgres) para que PostgreSQL lo utilice.
+const searchVector = "(setweight(to_tsvector('" + searchConfig + "', coalesce(tasks.name, '')), 'A') || " +
+	"setweight(to_tsvector('" + searchConfig + "', coalesce(tasks.description, '')), 'B'))"
+
+// searchHighlightOptions opciones de ts_headline para resaltar coincidencias
+const searchHighlightOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+// SearchOptions parámetros de una búsqueda de texto completo
+type SearchOptions struct {
+	Query  string // Texto a buscar: palabras, "frases entre comillas" y prefijos terminados en *
+	Limit  int    // Máximo de resultados (0 = DefaultPageSize)
+	Offset int    // Desplazamiento para paginar resultados
+}
+
+// SearchResult tarea encontrada junto con su relevancia y los fragmentos resaltados
+type SearchResult struct {
+	Task                 models.Task
+	Rank                 float64 // Relevancia según ts_rank (mayor es más relevante)
+	NameHighlight        string  // Nombre con las coincidencias envueltas en <mark></mark>
+	DescriptionHighlight string  // Fragmentos de la descripción con coincidencias resaltadas
+}
+
+// SearchPage resultado paginado de SearchTasks
+type SearchPage struct {
+	Results []SearchResult
+	Total   int64 // Total de tareas que coinciden con la búsqueda
+	Limit   int
+	Offset  int
+}
+
+// searchRow fila intermedia para escanear la tarea junto con las columnas calculadas
+type searchRow struct {
+	models.Task
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
+
+// buildTSQuery traduce la búsqueda del usuario a la sintaxis de to_tsquery.
+// Reglas:
+//   - Palabras sueltas se combinan con AND (&)
+//   - "frases entre comillas" exigen palabras consecutivas (<->)
+//   - Un término terminado en * busca por prefijo (:*)
+//
+// Los caracteres que no son letras ni dígitos se descartan para evitar errores de sintaxis.
+// Retorna: ErrInvalidQuery si la búsqueda no contiene ningún término utilizable
+func buildTSQuery(input string) (string, error) {
+	var terms []string
+	for i, chunk := range strings.Split(input, `"`) {
+		if i%2 == 1 {
+			// Contenido entre comillas: una sola frase
+			if phrase := phraseQuery(chunk); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(chunk) {
+			if term := phraseQuery(word); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return "", fmt.Errorf("%w: search query must contain at least one word", ErrInvalidQuery)
+	}
+	return strings.Join(terms, " & "), nil
+}
+
+// phraseQuery convierte un texto en una secuencia de lexemas consecutivos.
+// Si el texto termina en *, el último lexema se busca como prefijo.
+func phraseQuery(text string) string {
+	text = strings.TrimSpace(text)
+	prefix := strings.HasSuffix(text, "*")
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	if prefix {
+		words[len(words)-1] += ":*"
+	}
+	if len(words) == 1 {
+		return words[0]
+	}
+	return "(" + strings.Join(words, " <-> ") + ")"
+}
+
+// normalize aplica el tamaño de página por defecto y el máximo permitido
+// Retorna: ErrInvalidQuery si el offset es negativo
+func (o *SearchOptions) normalize() error {
+	if o.Limit <= 0 {
+		o.Limit = DefaultPageSize
+	}
+	if o.Limit > MaxPageSize {
+		o.Limit = MaxPageSize
+	}
+	if o.Offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// SearchTasks busca tareas por nombre y descripción usando el índice de texto completo
+// Recibe: opciones de búsqueda (texto, límite y offset)
+// Retorna:
+//   - Resultados ordenados por relevancia con fragmentos resaltados
+//   - ErrInvalidQuery si la búsqueda está vacía, o error de GORM
+//
+// Nota: Igual que en los listados por defecto, se excluyen las tareas de proyectos archivados.
+// En bases de datos distintas de PostgreSQL (SQLite) se usa la búsqueda por subcadena de searchTermsQuery.
+func (r *repository) SearchTasks(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	db := r.db.WithContext(ctx)
+	if db.Dialector.Name() != "postgres" {
+		return r.searchTermsQuery(ctx, opts)
+	}
+	tsQuery, err := buildTSQuery(opts.Query)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+
+	match := searchVector + " @@ to_tsquery('" + searchConfig + "', ?)"
+
+	var total int64
+	if err := db.Model(&models.Task{}).Where(match, tsQuery).Where(notInArchivedProject).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []searchRow
+	err = db.Raw(`
+		SELECT tasks.*,
+			ts_rank(`+searchVector+`, q.query) AS rank,
+			ts_headline('`+searchConfig+`', tasks.name, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS name_highlight,
+			ts_headline('`+searchConfig+`', tasks.description, q.query, '`+searchHighlightOptions+`') AS description_highlight
+		FROM tasks, to_tsquery('`+searchConfig+`', ?) AS q(query)
+		WHERE tasks.deleted_at IS NULL AND `+searchVector+` @@ q.query AND `+notInArchivedProject+`
+		ORDER BY rank DESC, tasks.id
+		LIMIT ? OFFSET ?`, tsQuery, opts.Limit, opts.Offset).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.searchPage(ctx, opts, total, rows)
+}
+
+// searchPage completa las filas encontradas con sus etiquetas y el campo blocked
+func (r *repository) searchPage(ctx context.Context, opts SearchOptions, total int64, rows []searchRow) (*SearchPage, error) {
+	tags, err := r.tagsByTask(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make([]*models.Task, len(rows))
+	for i := range rows {
+		blocked[i] = &rows[i].Task
+	}
+	if err := markBlocked(r.db.WithContext(ctx), blocked...); err != nil {
+		return nil, err
+	}
+
+	page := &SearchPage{Total: total, Limit: opts.Limit, Offset: opts.Offset}
+	for _, row := range rows {
+		row.Task.Tags = tags[row.ID]
+		page.Results = append(page.Results, SearchResult{
+			Task:                 row.Task,
+			Rank:                 row.Rank,
+			NameHighlight:        row.NameHighlight,
+			DescriptionHighlight: row.DescriptionHighlight,
+		})
+	}
+	return page, nil
+}
+
+// tagsByTask carga las etiquetas de las tareas encontradas, ya que la consulta Raw no admite Preload
+func (r *repository) tagsByTask(ctx context.Context, rows []searchRow) (map[uint][]models.Tag, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var tasks []models.Task
+	if err := r.db.WithContext(ctx).Select("id").Preload("Tags", orderTags).Find(&tasks, ids).Error; err != nil {
+		return nil, err
+	}
+	tags := make(map[uint][]models.Tag, len(tasks))
+	for _, t := range tasks {
+		tags[t.ID] = t.Tags
+	}
+	return tags, nil
+}
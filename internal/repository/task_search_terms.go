@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// Pesos de una coincidencia en el nombre y en la descripción, los mismos que aplica ts_rank
+// por defecto a los pesos A y B del índice de texto completo
+const (
+	nameTermWeight        = 1.0
+	descriptionTermWeight = 0.4
+)
+
+// searchTerms traduce la búsqueda del usuario a los términos de la búsqueda por subcadena que se usa
+// sin el texto completo de PostgreSQL (SQLite y el repositorio en memoria):
+//   - Cada palabra y cada "frase entre comillas" es un término que debe aparecer en el nombre o la descripción
+//   - El * final no cambia nada, ya que toda coincidencia por subcadena lo es también por prefijo
+//
+// Igual que en buildTSQuery, se descartan los caracteres que no son letras ni dígitos.
+// Retorna: términos en minúsculas, o ErrInvalidQuery si la búsqueda no contiene ninguno
+func searchTerms(input string) ([]string, error) {
+	var terms []string
+	for i, chunk := range strings.Split(input, `"`) {
+		if i%2 == 1 {
+			if phrase := phraseTerm(chunk); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(chunk) {
+			if term := phraseTerm(word); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: search query must contain at least one word", ErrInvalidQuery)
+	}
+	return terms, nil
+}
+
+// phraseTerm normaliza un texto como término de búsqueda: palabras en minúsculas separadas por un espacio
+func phraseTerm(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// matchTerms indica si todos los términos aparecen en el nombre o la descripción de la tarea
+// Retorna: si coincide y su relevancia (suma de los pesos de cada término en el nombre y en la descripción)
+func matchTerms(task *models.Task, terms []string) (bool, float64) {
+	name, description := strings.ToLower(task.Name), strings.ToLower(task.Description)
+	var rank float64
+	for _, term := range terms {
+		inName, inDescription := strings.Contains(name, term), strings.Contains(description, term)
+		if !inName && !inDescription {
+			return false, 0
+		}
+		if inName {
+			rank += nameTermWeight
+		}
+		if inDescription {
+			rank += descriptionTermWeight
+		}
+	}
+	return true, rank
+}
+
+// highlightTerms envuelve en <mark></mark> las apariciones de los términos en el texto
+// Las apariciones que se solapan se resaltan como una sola.
+func highlightTerms(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Las posiciones en minúsculas no corresponden al texto original (caracteres con otra longitud en UTF-8)
+		return text
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, term := range terms {
+		for from := 0; ; {
+			i := strings.Index(lower[from:], term)
+			if i < 0 {
+				break
+			}
+			spans = append(spans, span{from + i, from + i + len(term)})
+			from += i + len(term)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(spans); {
+		start, end := spans[i].start, spans[i].end
+		for i++; i < len(spans) && spans[i].start <= end; i++ {
+			if spans[i].end > end {
+				end = spans[i].end
+			}
+		}
+		b.WriteString(text[last:start])
+		b.WriteString("<mark>" + text[start:end] + "</mark>")
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// searchTermsQuery implementa SearchTasks con LIKE en las bases de datos sin el texto completo de PostgreSQL
+// Cada término debe aparecer en el nombre o la descripción (sin distinguir mayúsculas en caracteres ASCII);
+// la relevancia se calcula con los pesos de matchTerms y los fragmentos resaltados son el texto completo.
+func (r *repository) searchTermsQuery(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	terms, err := searchTerms(opts.Query)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Task{}).Where(notInArchivedProject)
+	rank := make([]string, 0, len(terms))
+	var rankVars []interface{}
+	for _, term := range terms {
+		pattern := "%" + term + "%"
+		query = query.Where("(LOWER(tasks.name) LIKE ? OR LOWER(tasks.description) LIKE ?)", pattern, pattern)
+		rank = append(rank, fmt.Sprintf("(CASE WHEN LOWER(tasks.name) LIKE ? THEN %g ELSE 0 END) + "+
+			"(CASE WHEN LOWER(tasks.description) LIKE ? THEN %g ELSE 0 END)", nameTermWeight, descriptionTermWeight))
+		rankVars = append(rankVars, pattern, pattern)
+	}
+
+	query = query.Session(&gorm.Session{}) // Reutilizable para el total y la página
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []searchRow
+	err = query.Select("tasks.*, ("+strings.Join(rank, " + ")+") AS rank", rankVars...).
+		Order("rank DESC, tasks.id").Limit(opts.Limit).Offset(opts.Offset).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].NameHighlight = highlightTerms(rows[i].Name, terms)
+		rows[i].DescriptionHighlight = highlightTerms(rows[i].Description, terms)
+	}
+	return r.searchPage(ctx, opts, total, rows)
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TrashOptions paginación de la papelera
+type TrashOptions struct {
+	Limit  int // Máximo de tareas por página (0 = DefaultPageSize)
+	Offset int // Desplazamiento para paginación
+}
+
+// purgeBatchSize tareas eliminadas definitivamente por transacción al vaciar la papelera
+const purgeBatchSize = 100
+
+// GetTrash obtiene una página de las tareas eliminadas, de la más reciente a la más antigua
+// Retorna: página con las tareas y el total en la papelera, ErrInvalidQuery o error de GORM
+func (r *repository) GetTrash(ctx context.Context, opts TrashOptions) (*TaskPage, error) {
+	if err := normalizePage(&opts.Limit, opts.Offset); err != nil {
+		return nil, err
+	}
+	trash := r.db.WithContext(ctx).Unscoped().Model(&models.Task{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := trash.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	tasks := []models.Task{}
+	err := trash.Preload("Tags", orderTags).Order(clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: "deleted_at"}, Desc: true},
+		{Column: clause.Column{Name: "id"}, Desc: true},
+	}}).Offset(opts.Offset).Limit(opts.Limit).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return &TaskPage{Tasks: tasks, Total: total, Limit: opts.Limit, Offset: opts.Offset}, nil
+}
+
+// GetDeletedTask busca una tarea en la papelera
+// Retorna: ErrTaskNotFound si no existe o no está eliminada, o error de GORM
+func (r *repository) GetDeletedTask(ctx context.Context, id uint) (*models.Task, error) {
+	var task models.Task
+	err := r.db.WithContext(ctx).Unscoped().Preload("Tags", orderTags).Where("deleted_at IS NOT NULL").First(&task, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("task with ID %d in trash: %w", id, translateError(err))
+	}
+	return &task, nil
+}
+
+// RestoreTask recupera una tarea de la papelera
+// Recibe: ID de la tarea y, opcionalmente, un nuevo nombre ("" conserva el nombre original)
+// Si la tarea padre ya no existe, la tarea se restaura en primer nivel. Las subtareas que tenía al
+// eliminarse no se restauran con ella: al eliminarla pasaron a depender de su padre.
+// Las comprobaciones se hacen en la misma transacción que la restauración, con la tarea bloqueada
+// y los cambios de jerarquía serializados (ver advisoryLock).
+// Retorna:
+//   - la tarea restaurada
+//   - ErrTaskNotFound si no está en la papelera, ErrDuplicateName si otra tarea del proyecto ya usa el nombre
+//   - ErrProjectNotFound si su proyecto fue eliminado, ErrStatusInUse si el flujo de trabajo del proyecto ya no
+//     incluye su estado, ValidationErrors si el nuevo nombre no es válido, o error de GORM
+func (r *repository) RestoreTask(ctx context.Context, id uint, name string) (*models.Task, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := forUpdate(tx.Unscoped()).Where("deleted_at IS NOT NULL").First(&task, id).Error; err != nil {
+			return fmt.Errorf("task with ID %d in trash: %w", id, translateError(err))
+		}
+		if name != "" {
+			task.Name = name
+			if err := task.Validate(); err != nil {
+				return err
+			}
+		}
+		if task.ProjectID != nil {
+			if err := projectExists(forShare(tx), *task.ProjectID); err != nil {
+				return err
+			}
+		}
+		if task.ParentID != nil {
+			if err := advisoryLock(tx, hierarchyLockKey); err != nil {
+				return err
+			}
+			if err := taskExists(tx, *task.ParentID); err != nil {
+				if !errors.Is(err, ErrTaskNotFound) {
+					return err
+				}
+				task.ParentID = nil
+			}
+		}
+		workflow, err := loadWorkflow(tx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err := restoreStatus(workflow, &task); err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Session(&gorm.Session{SkipHooks: true}).Model(&models.Task{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"deleted_at":      nil,
+				"name":            task.Name,
+				"parent_id":       task.ParentID,
+				"status_category": task.StatusCategory,
+				"version":         gorm.Expr("version + 1"),
+				"updated_at":      time.Now(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("task with ID %d: %w", id, translateError(err))
+		}
+		// La tarea vuelve a bloquear a las tareas que dependen de ella
+		return bumpBlockedBy(tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetTaskByID(ctx, id)
+}
+
+// PurgeTask elimina definitivamente una tarea, esté en la papelera o no, junto con sus etiquetas,
+// dependencias e historial de estados (la auditoría se conserva)
+// Recibe: ID de la tarea y versión esperada (0 para eliminar sin importar la versión)
+// Una tarea no eliminada se desvincula y se mueve a la papelera primero, igual que en DeleteTask.
+// Retorna: ErrTaskNotFound, ErrVersionConflict o error de GORM
+func (r *repository) PurgeTask(ctx context.Context, id uint, expectedVersion uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := forUpdate(tx.Unscoped()).Select("id", "parent_id", "version", "deleted_at").First(&task, id).Error; err != nil {
+			return fmt.Errorf("task with ID %d: %w", id, translateError(err))
+		}
+		if expectedVersion != 0 && task.Version != expectedVersion {
+			return fmt.Errorf("task with ID %d: %w", id, ErrVersionConflict)
+		}
+		if !task.DeletedAt.Valid {
+			if err := advisoryLock(tx, hierarchyLockKey); err != nil {
+				return err
+			}
+			if err := detachTask(tx, &task); err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.Task{}, id).Error; err != nil {
+				return err
+			}
+		}
+		return purgeTasks(tx, []uint{id})
+	})
+}
+
+// PurgeTrash elimina definitivamente las tareas que llevan en la papelera desde antes de la fecha indicada
+// Las tareas se eliminan por lotes, cada uno en su propia transacción.
+// Retorna: IDs de las tareas eliminadas (incluidas las de lotes anteriores a un error) y error de GORM
+func (r *repository) PurgeTrash(ctx context.Context, before time.Time) ([]uint, error) {
+	return r.purgeTrash(ctx, before, nil)
+}
+
+// purgeTrash implementa PurgeTrash; onBatch (opcional) se ejecuta en la transacción de cada lote,
+// después de eliminar sus tareas, y si retorna error el lote se revierte
+// Las tareas del lote se leen bloqueadas: una restauración concurrente espera al lote o lo hace omitir la tarea.
+func (r *repository) purgeTrash(ctx context.Context, before time.Time, onBatch func(tx *gorm.DB, ids []uint) error) ([]uint, error) {
+	var purged []uint
+	for {
+		var ids []uint
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := forUpdate(tx.Unscoped().Model(&models.Task{})).
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+				Order("id").Limit(purgeBatchSize).Pluck("id", &ids).Error
+			if err != nil || len(ids) == 0 {
+				return err
+			}
+			if err := purgeTasks(tx, ids); err != nil || onBatch == nil {
+				return err
+			}
+			return onBatch(tx, ids)
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged = append(purged, ids...)
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// detachTask desvincula una tarea que deja de existir: sus subtareas directas pasan a depender
+// de su padre (o quedan en primer nivel) y las tareas que bloqueaba cambian de versión
+func detachTask(tx *gorm.DB, task *models.Task) error {
+	err := tx.Session(&gorm.Session{SkipHooks: true}).Model(&models.Task{}).
+		Where("parent_id = ?", task.ID).
+		Updates(map[string]interface{}{
+			"parent_id":  task.ParentID,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		return err
+	}
+	return bumpBlockedBy(tx, task.ID)
+}
+
+// purgeTasks elimina definitivamente las tareas indicadas que están en la papelera y los registros que dependen de ellas
+// Las tareas que ya no están en la papelera (ej. restauradas) se conservan junto con sus registros.
+// Las tareas de la papelera que las tenían como padre quedan en primer nivel.
+func purgeTasks(tx *gorm.DB, ids []uint) error {
+	trashed := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.Task{}).
+		Select("id").Where("id IN ? AND deleted_at IS NOT NULL", ids)
+	err := tx.Unscoped().Session(&gorm.Session{SkipHooks: true}).Model(&models.Task{}).
+		Where("parent_id IN (?)", trashed).
+		Update("parent_id", nil).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("task_id IN (?)", trashed).Delete(&taskTag{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("blocker_id IN (?) OR blocked_id IN (?)", trashed, trashed).Delete(&models.TaskDependency{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("task_id IN (?)", trashed).Delete(&models.TaskStatusEvent{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.Task{}, ids).Error
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abrahamcruzc/task-manager-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// Errores centinela de los flujos de trabajo
+var (
+	ErrInvalidTransition = errors.New("status transition is not allowed by the workflow")     // El flujo no permite el cambio de estado
+	ErrStatusInUse       = errors.New("the workflow does not include statuses used by tasks") // Reemplazar el flujo dejaría tareas con estados inexistentes
+	ErrConflict          = errors.New("the workflow was modified concurrently")               // Otra solicitud creó el flujo del proyecto al mismo tiempo
+)
+
+// WorkflowRepository define las operaciones sobre los flujos de trabajo de los proyectos
+// El flujo por defecto (models.DefaultWorkflow) no se almacena y no se puede modificar.
+type WorkflowRepository interface {
+	GetWorkflows(ctx context.Context) ([]models.Workflow, error)
+	GetProjectWorkflow(ctx context.Context, projectID uint) (*models.Workflow, error)
+	SetProjectWorkflow(ctx context.Context, projectID uint, workflow *models.Workflow) error
+	ResetProjectWorkflow(ctx context.Context, projectID uint) error
+}
+
+// workflowRepository implementación concreta de WorkflowRepository usando GORM
+type workflowRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkflowRepository factory para crear instancias del repositorio de flujos de trabajo
+// Recibe: conexión a la base de datos (*gorm.DB)
+// Retorna: implementación de WorkflowRepository lista para usar
+func NewWorkflowRepository(db *gorm.DB) WorkflowRepository {
+	return &workflowRepository{db: db}
+}
+
+// preloadWorkflow precarga los estados (en orden) y las transiciones de un flujo
+func preloadWorkflow(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Statuses", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Transitions", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+// GetWorkflows obtiene el flujo por defecto seguido de los flujos propios de cada proyecto
+func (r *workflowRepository) GetWorkflows(ctx context.Context) ([]models.Workflow, error) {
+	var custom []models.Workflow
+	if err := preloadWorkflow(r.db.WithContext(ctx)).Order("project_id").Find(&custom).Error; err != nil {
+		return nil, err
+	}
+	return append([]models.Workflow{*models.DefaultWorkflow()}, custom...), nil
+}
+
+// GetProjectWorkflow obtiene el flujo que aplica a las tareas del proyecto
+// (el propio del proyecto o, si no tiene, el flujo por defecto)
+// Retorna: ErrProjectNotFound si el proyecto no existe, o error de GORM
+func (r *workflowRepository) GetProjectWorkflow(ctx context.Context, projectID uint) (*models.Workflow, error) {
+	db := r.db.WithContext(ctx)
+	if err := projectExists(db, projectID); err != nil {
+		return nil, err
+	}
+	return loadWorkflow(db, &projectID)
+}
+
+// SetProjectWorkflow crea o reemplaza el flujo propio del proyecto en una sola transacción
+// Las tareas del proyecto conservan su estado, por lo que todos los estados en uso deben existir en el nuevo flujo;
+// la categoría de esas tareas se actualiza según el nuevo flujo.
+// Retorna: ValidationErrors si el flujo no es válido, ErrProjectNotFound, ErrStatusInUse, ErrConflict o error de GORM
+func (r *workflowRepository) SetProjectWorkflow(ctx context.Context, projectID uint, workflow *models.Workflow) error {
+	if err := workflow.Validate(); err != nil {
+		return err
+	}
+	for i := range workflow.Statuses {
+		workflow.Statuses[i].Position = i
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProjectTasks(tx, projectID); err != nil {
+			return err
+		}
+		if err := checkStatusesInUse(tx, projectID, workflow); err != nil {
+			return err
+		}
+		if err := deleteWorkflow(tx, projectID); err != nil {
+			return err
+		}
+		workflow.ID = 0
+		workflow.ProjectID = &projectID
+		if err := tx.Create(workflow).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("project with ID %d: %w", projectID, ErrConflict)
+			}
+			return err
+		}
+		return recategorizeTasks(tx, projectID, workflow)
+	})
+}
+
+// ResetProjectWorkflow elimina el flujo propio del proyecto, que vuelve a usar el flujo por defecto
+// Retorna: ErrProjectNotFound, ErrStatusInUse si alguna tarea usa un estado que no existe en el flujo por defecto,
+// o error de GORM
+func (r *workflowRepository) ResetProjectWorkflow(ctx context.Context, projectID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProjectTasks(tx, projectID); err != nil {
+			return err
+		}
+		workflow := models.DefaultWorkflow()
+		if err := checkStatusesInUse(tx, projectID, workflow); err != nil {
+			return err
+		}
+		if err := deleteWorkflow(tx, projectID); err != nil {
+			return err
+		}
+		return recategorizeTasks(tx, projectID, workflow)
+	})
+}
+
+// lockProjectTasks bloquea las tareas del proyecto (incluidas las de la papelera) y después el proyecto
+// hasta que termina la transacción. Las escrituras de tareas leen el proyecto con forShare después de bloquear
+// la tarea, de modo que ninguna puede guardar un estado validado contra el flujo que se está reemplazando;
+// bloquear primero las tareas respeta ese mismo orden y evita interbloqueos.
+// Retorna: ErrProjectNotFound o error de GORM
+func lockProjectTasks(tx *gorm.DB, projectID uint) error {
+	var ids []uint
+	err := forUpdate(tx.Unscoped().Model(&models.Task{})).Where("project_id = ?", projectID).Order("id").Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	return projectExists(forUpdate(tx), projectID)
+}
+
+// checkStatusesInUse retorna ErrStatusInUse si alguna tarea del proyecto tiene un estado que no existe
+// en el flujo indicado; incluye las tareas de la papelera, que conservan su estado al restaurarse
+func checkStatusesInUse(tx *gorm.DB, projectID uint, workflow *models.Workflow) error {
+	var used []models.Status
+	err := tx.Unscoped().Model(&models.Task{}).Where("project_id = ?", projectID).Distinct().Pluck("status", &used).Error
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, status := range used {
+		if _, ok := workflow.Status(status); !ok {
+			missing = append(missing, string(status))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrStatusInUse, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// recategorizeTasks actualiza la categoría de las tareas del proyecto cuyo estado cambió de categoría,
+// incrementando su versión ya que cambia su representación
+func recategorizeTasks(tx *gorm.DB, projectID uint, workflow *models.Workflow) error {
+	for _, status := range workflow.Statuses {
+		err := tx.Unscoped().Session(&gorm.Session{SkipHooks: true}).Model(&models.Task{}).
+			Where("project_id = ? AND status = ?", projectID, status.Name).
+			Where("status_category IS NULL OR status_category <> ?", status.Category).
+			Updates(map[string]interface{}{
+				"status_category": status.Category,
+				"version":         gorm.Expr("version + 1"),
+				"updated_at":      time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteWorkflow elimina el flujo propio del proyecto (si existe) junto con sus estados y transiciones
+func deleteWorkflow(tx *gorm.DB, projectID uint) error {
+	var ids []uint
+	if err := tx.Model(&models.Workflow{}).Where("project_id = ?", projectID).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("workflow_id IN ?", ids).Delete(&models.WorkflowStatus{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("workflow_id IN ?", ids).Delete(&models.WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.Workflow{}, ids).Error
+}
+
+// loadWorkflow obtiene el flujo que aplica a las tareas del proyecto indicado
+// Las tareas sin proyecto y los proyectos sin flujo propio usan models.DefaultWorkflow.
+func loadWorkflow(db *gorm.DB, projectID *uint) (*models.Workflow, error) {
+	if projectID == nil {
+		return models.DefaultWorkflow(), nil
+	}
+	var workflows []models.Workflow
+	if err := preloadWorkflow(db).Where("project_id = ?", *projectID).Limit(1).Find(&workflows).Error; err != nil {
+		return nil, err
+	}
+	if len(workflows) == 0 {
+		return models.DefaultWorkflow(), nil
+	}
+	return &workflows[0], nil
+}
+
+// resolveStatus valida el estado de la tarea contra el flujo de trabajo de su proyecto y asigna su categoría
+// Recibe: la tarea y su estado anterior ("" si la tarea es nueva)
+// Una tarea nueva sin estado recibe el estado inicial del flujo.
+// Retorna: ValidationErrors si el estado no existe en el flujo, ErrInvalidTransition, o error de GORM
+func resolveStatus(db *gorm.DB, task *models.Task, previous models.Status) error {
+	workflow, err := loadWorkflow(db, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	return applyWorkflow(workflow, task, previous)
+}
+
+// applyWorkflow valida el estado de la tarea contra el flujo indicado y asigna su categoría (ver resolveStatus)
+func applyWorkflow(workflow *models.Workflow, task *models.Task, previous models.Status) error {
+	if task.Status == "" && previous == "" {
+		task.Status = workflow.Initial()
+	}
+
+	status, ok := workflow.Status(task.Status)
+	if !ok {
+		return models.ValidationErrors{{
+			Field:   "status",
+			Message: fmt.Sprintf("invalid status %q: must be one of %s", task.Status, strings.Join(workflow.StatusNames(), ", ")),
+		}}
+	}
+	if previous != "" && !workflow.CanTransition(previous, task.Status) {
+		return fmt.Errorf("task with ID %d from %q to %q: %w", task.ID, previous, task.Status, ErrInvalidTransition)
+	}
+	task.StatusCategory = status.Category
+	return nil
+}
+
+// restoreStatus comprueba que el estado de una tarea que sale de la papelera exista en el flujo de su proyecto,
+// que pudo cambiar mientras estaba eliminada, y asigna la categoría que tiene en ese flujo
+// Retorna: ErrStatusInUse si el flujo ya no incluye el estado
+func restoreStatus(workflow *models.Workflow, task *models.Task) error {
+	if _, ok := workflow.Status(task.Status); !ok {
+		return fmt.Errorf("task with ID %d: %w: %s", task.ID, ErrStatusInUse, task.Status)
+	}
+	return applyWorkflow(workflow, task, "")
+}
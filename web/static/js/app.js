@@ -10,22 +10,74 @@ class App {
         document.getElementById('addTaskBtn').addEventListener('click', () => this.ui.showTaskModal());
         document.getElementById('saveTaskBtn').addEventListener('click', () => this.saveTask());
         document.getElementById('confirmDeleteBtn').addEventListener('click', () => this.deleteTask());
+        document.getElementById('searchInput').addEventListener('input', (e) => this.onSearchInput(e.target.value));
+        document.getElementById('priorityFilter').addEventListener('change', (e) => {
+            this.priorityFilter = e.target.value;
+            this.loadTasks();
+        });
+        document.getElementById('loadMoreBtn').addEventListener('click', () => this.loadMoreTasks());
+
+        // Status options depend on each task's workflow
+        try {
+            this.ui.setWorkflows(await this.taskService.getWorkflows());
+        } catch (error) {
+            this.ui.showToast('Failed to load workflows', 'danger');
+        }
 
         // Load initial tasks
         await this.loadTasks();
     }
 
+    onSearchInput(query) {
+        // Debounce keystrokes so the server is only queried once typing pauses
+        clearTimeout(this.searchTimer);
+        this.searchTimer = setTimeout(() => {
+            this.searchQuery = query.trim();
+            this.loadTasks();
+        }, 300);
+    }
+
+    // Only the first page is loaded; further pages are fetched with the "Load more" button
     async loadTasks() {
         try {
             this.ui.showLoading();
-            const tasks = await this.taskService.getAllTasks();
+            this.nextCursor = '';
+            let tasks;
+            if (this.searchQuery) {
+                // Search results are ranked by relevance, so the priority filter is applied client-side
+                tasks = await this.taskService.searchTasks(this.searchQuery);
+                if (this.priorityFilter) {
+                    tasks = tasks.filter(t => t.priority === this.priorityFilter);
+                }
+            } else {
+                const page = await this.taskService.getTasks({ priority: this.priorityFilter });
+                tasks = page.items;
+                this.nextCursor = page.next_cursor || '';
+            }
             this.ui.displayTasks(tasks);
+            this.ui.setLoadMore(!!this.nextCursor);
         } catch (error) {
             this.ui.showToast('Failed to load tasks', 'danger');
             this.ui.hideLoading();
         }
     }
 
+    async loadMoreTasks() {
+        if (!this.nextCursor) {
+            return;
+        }
+        try {
+            this.ui.setLoadMore(true, true);
+            const page = await this.taskService.getTasks({ priority: this.priorityFilter }, this.nextCursor);
+            this.nextCursor = page.next_cursor || '';
+            this.ui.appendTasks(page.items);
+            this.ui.setLoadMore(!!this.nextCursor);
+        } catch (error) {
+            this.ui.showToast('Failed to load more tasks', 'danger');
+            this.ui.setLoadMore(true);
+        }
+    }
+
     async saveTask() {
         try {
             const taskData = this.ui.getFormData();
@@ -45,11 +97,8 @@ class App {
 
     async editTask(taskId) {
         try {
-            const tasks = await this.taskService.getAllTasks();
-            const task = tasks.find(t => t.ID === taskId);
-            if (task) {
-                this.ui.showTaskModal(task);
-            }
+            const task = await this.taskService.getTask(taskId);
+            this.ui.showTaskModal(task);
         } catch (error) {
             this.ui.showToast('Failed to load task details', 'danger');
         }
@@ -3,9 +3,33 @@ class TaskService {
         this.baseUrl = '/tasks';
     }
 
-    async getAllTasks() {
+    // Builds a readable message from an application/problem+json error response
+    async errorMessage(response, fallback) {
         try {
-            const response = await fetch(this.baseUrl);
+            const problem = await response.json();
+            if (problem.errors && problem.errors.length) {
+                return problem.errors.map(e => e.message).join('. ');
+            }
+            return problem.detail || problem.title || fallback;
+        } catch (e) {
+            return fallback;
+        }
+    }
+
+    async getWorkflows() {
+        const response = await fetch('/workflows');
+        if (!response.ok) throw new Error('Failed to fetch workflows');
+        const data = await response.json();
+        return data.items;
+    }
+
+    // Fetches one page of tasks; pass the previous page's next_cursor to get the following one
+    async getTasks(filters = {}, cursor = '') {
+        try {
+            const params = new URLSearchParams({ limit: '50' });
+            if (filters.priority) params.set('priority', filters.priority);
+            if (cursor) params.set('cursor', cursor);
+            const response = await fetch(`${this.baseUrl}?${params}`);
             if (!response.ok) throw new Error('Failed to fetch tasks');
             return await response.json();
         } catch (error) {
@@ -14,6 +38,30 @@ class TaskService {
         }
     }
 
+    async getTask(taskId) {
+        try {
+            const response = await fetch(`${this.baseUrl}/${taskId}`);
+            if (!response.ok) throw new Error('Failed to fetch task');
+            return await response.json();
+        } catch (error) {
+            console.error('Error fetching task:', error);
+            throw error;
+        }
+    }
+
+    async searchTasks(query) {
+        try {
+            const params = new URLSearchParams({ q: query, limit: '200' });
+            const response = await fetch(`${this.baseUrl}/search?${params}`);
+            if (!response.ok) throw new Error('Failed to search tasks');
+            const page = await response.json();
+            return page.items.map(result => result.task);
+        } catch (error) {
+            console.error('Error searching tasks:', error);
+            throw error;
+        }
+    }
+
     async createTask(taskData) {
         try {
             const response = await fetch(this.baseUrl, {
@@ -24,8 +72,7 @@ class TaskService {
                 body: JSON.stringify(taskData)
             });
             if (!response.ok) {
-                const error = await response.text();
-                throw new Error(error || 'Failed to create task');
+                throw new Error(await this.errorMessage(response, 'Failed to create task'));
             }
             return await response.json();
         } catch (error) {
@@ -36,16 +83,23 @@ class TaskService {
 
     async updateTask(taskId, taskData) {
         try {
+            const headers = {
+                'Content-Type': 'application/json',
+            };
+            // Only overwrite the version we loaded; the server answers 412 if someone else saved first
+            if (taskData.version) {
+                headers['If-Match'] = `"${taskData.version}"`;
+            }
             const response = await fetch(`${this.baseUrl}/${taskId}`, {
                 method: 'PUT',
-                headers: {
-                    'Content-Type': 'application/json',
-                },
+                headers,
                 body: JSON.stringify(taskData)
             });
+            if (response.status === 412) {
+                throw new Error('This task was modified by someone else. Reload it and try again.');
+            }
             if (!response.ok) {
-                const error = await response.text();
-                throw new Error(error || 'Failed to update task');
+                throw new Error(await this.errorMessage(response, 'Failed to update task'));
             }
             return await response.json();
         } catch (error) {
@@ -2,27 +2,108 @@ class UI {
     constructor() {
         this.taskList = document.getElementById('taskList');
         this.loadingIndicator = document.getElementById('loadingIndicator');
+        this.loadMoreContainer = document.getElementById('loadMoreContainer');
+        this.loadMoreBtn = document.getElementById('loadMoreBtn');
         this.taskModal = new bootstrap.Modal(document.getElementById('taskModal'));
         this.deleteModal = new bootstrap.Modal(document.getElementById('deleteModal'));
         this.currentTaskId = null;
+        this.workflows = [];
+    }
+
+    setWorkflows(workflows) {
+        this.workflows = workflows;
+    }
+
+    // Tasks without a project, or in a project without its own workflow, use the default one
+    getWorkflow(projectId) {
+        return this.workflows.find(w => w.project_id === projectId)
+            || this.workflows.find(w => w.project_id === null);
+    }
+
+    // New tasks can pick any status; existing ones only the statuses their workflow allows next
+    populateStatusOptions(task) {
+        const select = document.getElementById('taskStatus');
+        const workflow = this.getWorkflow(task && task.project_id ? task.project_id : null);
+        const statuses = workflow ? workflow.statuses : [];
+        const allowed = statuses.filter(s => !task
+            || s.name === task.status
+            || workflow.transitions.some(t => t.from === task.status && t.to === s.name));
+        select.innerHTML = allowed
+            .map(s => `<option value="${s.name}">${s.name}</option>`)
+            .join('');
+        select.value = task ? task.status : (statuses.length ? statuses[0].name : '');
     }
 
     showLoading() {
         this.loadingIndicator.style.display = 'block';
         this.taskList.innerHTML = '';
+        this.setLoadMore(false);
+    }
+
+    // Shows the "Load more" button while there are more pages; busy disables it during a request
+    setLoadMore(visible, busy = false) {
+        this.loadMoreContainer.classList.toggle('d-none', !visible);
+        this.loadMoreBtn.disabled = busy;
     }
 
     hideLoading() {
         this.loadingIndicator.style.display = 'none';
     }
 
-    getStatusBadgeClass(status) {
-        const statusClasses = {
-            'To do': 'bg-secondary',
-            'In progress': 'bg-primary',
-            'Completed': 'bg-success'
+    // Statuses are configurable per project, so the color follows the status category
+    getStatusBadgeClass(category) {
+        const categoryClasses = {
+            'todo': 'bg-secondary',
+            'active': 'bg-primary',
+            'done': 'bg-success'
+        };
+        return categoryClasses[category] || 'bg-secondary';
+    }
+
+    getPriorityBadgeClass(priority) {
+        const priorityClasses = {
+            'low': 'bg-secondary',
+            'medium': 'bg-info',
+            'high': 'bg-warning text-dark',
+            'urgent': 'bg-danger'
         };
-        return statusClasses[status] || 'bg-secondary';
+        return priorityClasses[priority] || 'bg-secondary';
+    }
+
+    getTagBadges(task) {
+        if (!task.tags || task.tags.length === 0) {
+            return '';
+        }
+        const badges = task.tags
+            .map(tag => `<span class="badge rounded-pill text-bg-light me-1">#${tag.name}</span>`)
+            .join('');
+        return `<div class="mb-2">${badges}</div>`;
+    }
+
+    isOverdue(task) {
+        return !!task.due_at && task.status_category !== 'done' && new Date(task.due_at) < new Date();
+    }
+
+    getDueLabel(task) {
+        if (!task.due_at) {
+            return '';
+        }
+        const cls = this.isOverdue(task) ? 'text-danger' : 'text-muted';
+        return `<small class="${cls}"><i class="bi bi-calendar-event"></i> Due ${new Date(task.due_at).toLocaleString()}</small>`;
+    }
+
+    // Convierte una fecha ISO al formato local que espera <input type="datetime-local">
+    toLocalInput(value) {
+        if (!value) {
+            return '';
+        }
+        const d = new Date(value);
+        d.setMinutes(d.getMinutes() - d.getTimezoneOffset());
+        return d.toISOString().slice(0, 16);
+    }
+
+    fromLocalInput(value) {
+        return value ? new Date(value).toISOString() : null;
     }
 
     displayTasks(tasks) {
@@ -38,6 +119,10 @@ class UI {
             return;
         }
 
+        this.appendTasks(tasks);
+    }
+
+    appendTasks(tasks) {
         tasks.forEach(task => {
             const taskCard = document.createElement('div');
             taskCard.className = 'col-md-4 mb-4';
@@ -46,9 +131,15 @@ class UI {
                     <div class="card-body">
                         <div class="d-flex justify-content-between align-items-start mb-2">
                             <h5 class="card-title mb-0">${task.name}</h5>
-                            <span class="badge ${this.getStatusBadgeClass(task.status)}">${task.status}</span>
+                            <div class="d-flex gap-1">
+                                <span class="badge ${this.getPriorityBadgeClass(task.priority)}">${task.priority}</span>
+                                <span class="badge ${this.getStatusBadgeClass(task.status_category)}">${task.status}</span>
+                                ${task.blocked ? '<span class="badge text-bg-dark" title="Blocked by unfinished tasks">blocked</span>' : ''}
+                            </div>
                         </div>
                         <p class="card-text">${task.description}</p>
+                        ${this.getTagBadges(task)}
+                        ${this.getDueLabel(task)}
                     </div>
                     <div class="card-footer bg-transparent border-top-0">
                         <div class="btn-group w-100">
@@ -72,13 +163,18 @@ class UI {
         const taskId = document.getElementById('taskId');
         const taskName = document.getElementById('taskName');
         const taskDescription = document.getElementById('taskDescription');
-        const taskStatus = document.getElementById('taskStatus');
 
         modalTitle.textContent = task ? 'Edit Task' : 'Add New Task';
         taskId.value = task ? task.ID : '';
+        document.getElementById('taskVersion').value = task ? task.version : '';
+        document.getElementById('taskProjectId').value = task && task.project_id ? task.project_id : '';
+        document.getElementById('taskParentId').value = task && task.parent_id ? task.parent_id : '';
         taskName.value = task ? task.name : '';
         taskDescription.value = task ? task.description : '';
-        taskStatus.value = task ? task.status : 'To do';
+        this.populateStatusOptions(task);
+        document.getElementById('taskPriority').value = task ? task.priority : 'medium';
+        document.getElementById('taskStartAt').value = task ? this.toLocalInput(task.start_at) : '';
+        document.getElementById('taskDueAt').value = task ? this.toLocalInput(task.due_at) : '';
 
         this.taskModal.show();
     }
@@ -120,9 +216,16 @@ class UI {
     getFormData() {
         return {
             ID: parseInt(document.getElementById('taskId').value) || undefined,
+            version: parseInt(document.getElementById('taskVersion').value) || undefined,
+            // PUT replaces the whole task, so keep the project and parent it belongs to
+            project_id: parseInt(document.getElementById('taskProjectId').value) || null,
+            parent_id: parseInt(document.getElementById('taskParentId').value) || null,
             name: document.getElementById('taskName').value,
             description: document.getElementById('taskDescription').value,
-            status: document.getElementById('taskStatus').value
+            status: document.getElementById('taskStatus').value,
+            priority: document.getElementById('taskPriority').value,
+            start_at: this.fromLocalInput(document.getElementById('taskStartAt').value),
+            due_at: this.fromLocalInput(document.getElementById('taskDueAt').value)
         };
     }
 }